@@ -0,0 +1,290 @@
+package hcl
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// OverflowPolicy decides what happens when the async queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the queue has space
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the line written
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest line in the queue
+	OverflowDropOldest
+	// OverflowDropBelow drops lines below AsyncOpts.DropLevel
+	// and waits for lines of DropLevel or above
+	OverflowDropBelow
+)
+
+// AsyncOpts configures the asynchronous output of WithAsync
+type AsyncOpts struct {
+	// QueueSize is the number of lines queued (default 1024)
+	QueueSize int
+	// Policy decides what happens when the queue is full
+	Policy OverflowPolicy
+	// DropLevel is the level below which lines are dropped by OverflowDropBelow
+	DropLevel hclog.Level
+	// ReportInterval is the interval dropped lines are reported (default 10s)
+	// a negative interval disables the reports
+	ReportInterval time.Duration
+}
+
+// WithAsync is used to create a logger writing asynchronously
+// lines are queued and written in the background
+// use Flush to drain the queue, Close is required to stop the background goroutines
+func WithAsync(opts AsyncOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.async = &opts
+	}
+}
+
+// asyncEntry is a queued line
+// entries with flushed set are flush markers
+type asyncEntry struct {
+	level   hclog.Level
+	data    []byte
+	flushed chan struct{}
+}
+
+// asyncWriter queues lines and writes them in the background
+type asyncWriter struct {
+	w    io.Writer
+	opts AsyncOpts
+
+	// mu guards closed, writes hold a read lock
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan asyncEntry
+	done    chan struct{}
+	stop    chan struct{}
+	dropped atomic.Uint64
+	// wmu serializes the writes of the queue and the reports to w
+	wmu sync.Mutex
+
+	// report is called with the number of lines dropped since the last report
+	// it writes to w
+	report atomic.Pointer[func(dropped uint64)]
+}
+
+func newAsyncWriter(w io.Writer, opts AsyncOpts) *asyncWriter {
+	if opts.QueueSize < 1 {
+		opts.QueueSize = 1024
+	}
+	if opts.ReportInterval == 0 {
+		opts.ReportInterval = 10 * time.Second
+	}
+	aw := &asyncWriter{
+		w:     w,
+		opts:  opts,
+		queue: make(chan asyncEntry, opts.QueueSize),
+		done:  make(chan struct{}),
+		stop:  make(chan struct{}),
+	}
+	go aw.run()
+	if opts.ReportInterval > 0 {
+		go aw.runReport()
+	}
+	return aw
+}
+
+// Write queues p at Info level
+func (aw *asyncWriter) Write(p []byte) (int, error) {
+	return aw.LevelWrite(hclog.Info, p)
+}
+
+// LevelWrite queues p
+// it implements hclog.LevelWriter
+func (aw *asyncWriter) LevelWrite(level hclog.Level, p []byte) (int, error) {
+	aw.mu.RLock()
+	defer aw.mu.RUnlock()
+	if aw.closed {
+		// write late lines synchronously
+		return writeLevel(aw.w, level, p)
+	}
+	// the caller reuses p
+	e := asyncEntry{level: level, data: append([]byte(nil), p...)}
+	select {
+	case aw.queue <- e:
+		return len(p), nil
+	default:
+	}
+	switch aw.opts.Policy {
+	case OverflowDropNewest:
+		aw.drop()
+	case OverflowDropOldest:
+		aw.enqueueDropOldest(e)
+	case OverflowDropBelow:
+		if level < aw.opts.DropLevel {
+			aw.drop()
+			break
+		}
+		aw.queue <- e
+	default:
+		aw.queue <- e
+	}
+	return len(p), nil
+}
+
+// enqueueDropOldest queues e and drops the oldest entries to make space
+func (aw *asyncWriter) enqueueDropOldest(e asyncEntry) {
+	for {
+		select {
+		case aw.queue <- e:
+			return
+		default:
+		}
+		select {
+		case old := <-aw.queue:
+			if old.flushed != nil {
+				// all entries before the marker are dequeued
+				close(old.flushed)
+				continue
+			}
+			aw.drop()
+		default:
+		}
+	}
+}
+
+func (aw *asyncWriter) run() {
+	defer close(aw.done)
+	for e := range aw.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		// errors cannot be logged: we are the log
+		aw.wmu.Lock()
+		if _, err := writeLevel(aw.w, e.level, e.data); err != nil {
+			writeErrors.Add(1)
+		}
+		aw.wmu.Unlock()
+	}
+}
+
+func (aw *asyncWriter) runReport() {
+	t := time.NewTicker(aw.opts.ReportInterval)
+	defer t.Stop()
+	var reported uint64
+	for {
+		select {
+		case <-aw.stop:
+			return
+		case <-t.C:
+			d := aw.dropped.Load()
+			if report := aw.report.Load(); d > reported && report != nil {
+				// the report bypasses the queue: it is full if lines are dropped
+				aw.wmu.Lock()
+				(*report)(d - reported)
+				aw.wmu.Unlock()
+			}
+			reported = d
+		}
+	}
+}
+
+// drop counts a dropped line
+func (aw *asyncWriter) drop() {
+	aw.dropped.Add(1)
+	droppedLines.Add(1)
+}
+
+// Dropped returns the number of lines dropped
+func (aw *asyncWriter) Dropped() uint64 {
+	return aw.dropped.Load()
+}
+
+// Flush waits until all lines queued before are written
+func (aw *asyncWriter) Flush(ctx context.Context) error {
+	aw.mu.RLock()
+	if aw.closed {
+		aw.mu.RUnlock()
+		return nil
+	}
+	e := asyncEntry{flushed: make(chan struct{})}
+	select {
+	case aw.queue <- e:
+		aw.mu.RUnlock()
+	case <-ctx.Done():
+		aw.mu.RUnlock()
+		return ctx.Err()
+	}
+	select {
+	case <-e.flushed:
+		return flushWriter(ctx, aw.w)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reopen writes the queued lines and reopens the underlying writer
+// it implements Reopener
+func (aw *asyncWriter) Reopen() error {
+	r, ok := aw.w.(Reopener)
+	if !ok {
+		return errNotReopener
+	}
+	if err := aw.Flush(context.Background()); err != nil {
+		return err
+	}
+	return r.Reopen()
+}
+
+// Close writes all queued lines and closes the underlying writer
+// lines written after Close are written synchronously
+func (aw *asyncWriter) Close() error {
+	aw.mu.Lock()
+	if aw.closed {
+		aw.mu.Unlock()
+		return nil
+	}
+	aw.closed = true
+	close(aw.queue)
+	close(aw.stop)
+	aw.mu.Unlock()
+	<-aw.done
+	return closeWriter(aw.w)
+}
+
+// writeLevel writes p to w passing the level if w is a hclog.LevelWriter
+func writeLevel(w io.Writer, level hclog.Level, p []byte) (int, error) {
+	if lw, ok := w.(hclog.LevelWriter); ok {
+		return lw.LevelWrite(level, p)
+	}
+	return w.Write(p)
+}
+
+// flusher is implemented by outputs buffering lines
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// flushWriter flushes w if it buffers lines
+func flushWriter(ctx context.Context, w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// closeWriter closes w if it can be closed
+// stdout and stderr are never closed
+func closeWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
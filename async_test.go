@@ -0,0 +1,163 @@
+package hcl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// gateWriter blocks writes until the gate is opened
+type gateWriter struct {
+	mu      sync.Mutex
+	lines   []string
+	started chan struct{}
+	gate    chan struct{}
+	closed  bool
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+func (w *gateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *gateWriter) Lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.lines...)
+}
+
+// fillQueue writes a line taken by the background writer
+// and fills the queue of size 2
+func fillQueue(t *testing.T, aw *asyncWriter, gw *gateWriter) {
+	aw.LevelWrite(hclog.Info, []byte("1\n"))
+	select {
+	case <-gw.started:
+	case <-time.After(time.Second):
+		t.Fatal("line not taken by the writer")
+	}
+	aw.LevelWrite(hclog.Info, []byte("2\n"))
+	aw.LevelWrite(hclog.Info, []byte("3\n"))
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	gw := newGateWriter()
+	aw := newAsyncWriter(gw, AsyncOpts{QueueSize: 2, Policy: OverflowDropNewest, ReportInterval: -1})
+	fillQueue(t, aw, gw)
+	aw.LevelWrite(hclog.Error, []byte("4\n"))
+	aw.LevelWrite(hclog.Error, []byte("5\n"))
+	close(gw.gate)
+	assert.NoError(t, aw.Flush(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3"}, gw.Lines())
+	assert.Equal(t, uint64(2), aw.Dropped())
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	gw := newGateWriter()
+	aw := newAsyncWriter(gw, AsyncOpts{QueueSize: 2, Policy: OverflowDropOldest, ReportInterval: -1})
+	fillQueue(t, aw, gw)
+	aw.LevelWrite(hclog.Info, []byte("4\n"))
+	aw.LevelWrite(hclog.Info, []byte("5\n"))
+	close(gw.gate)
+	assert.NoError(t, aw.Flush(context.Background()))
+	assert.Equal(t, []string{"1", "4", "5"}, gw.Lines())
+	assert.Equal(t, uint64(2), aw.Dropped())
+}
+
+func TestAsyncDropBelow(t *testing.T) {
+	gw := newGateWriter()
+	aw := newAsyncWriter(gw, AsyncOpts{QueueSize: 2, Policy: OverflowDropBelow, DropLevel: hclog.Warn, ReportInterval: -1})
+	fillQueue(t, aw, gw)
+	aw.LevelWrite(hclog.Info, []byte("4\n"))
+	written := make(chan struct{})
+	go func() {
+		aw.LevelWrite(hclog.Warn, []byte("5\n"))
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("warn line must block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(gw.gate)
+	<-written
+	assert.NoError(t, aw.Flush(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3", "5"}, gw.Lines())
+	assert.Equal(t, uint64(1), aw.Dropped())
+}
+
+func TestAsyncBlock(t *testing.T) {
+	gw := newGateWriter()
+	aw := newAsyncWriter(gw, AsyncOpts{QueueSize: 2, ReportInterval: -1})
+	fillQueue(t, aw, gw)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, aw.Flush(ctx), context.DeadlineExceeded)
+	close(gw.gate)
+	for i := 4; i < 10; i++ {
+		aw.LevelWrite(hclog.Info, []byte{byte('0' + i), '\n'})
+	}
+	assert.NoError(t, aw.Close())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, gw.Lines())
+	assert.Equal(t, uint64(0), aw.Dropped())
+	assert.True(t, gw.closed)
+
+	// lines are written synchronously after close
+	aw.LevelWrite(hclog.Info, []byte("late\n"))
+	assert.Equal(t, "late", gw.Lines()[9])
+	assert.NoError(t, aw.Flush(context.Background()))
+	assert.NoError(t, aw.Close())
+}
+
+func TestWithAsync(t *testing.T) {
+	keepDefault(t)
+	gw := newGateWriter()
+	l := New(WithName("async"), WithLevel(hclog.Info), WithWriter(gw), WithStdlib(false),
+		WithAsync(AsyncOpts{QueueSize: 1, Policy: OverflowDropNewest, ReportInterval: 10 * time.Millisecond}))
+	l.Info("text to output")
+	<-gw.started
+	l.Info("queued")
+	l.Info("dropped")
+	l.Info("dropped")
+	// the report is written directly when the writer proceeds
+	go func() {
+		for range gw.started {
+		}
+	}()
+	close(gw.gate)
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, l.Flush(context.Background()))
+		for _, line := range gw.Lines() {
+			if strings.HasSuffix(line, "[WARN]  async: dropped log lines: count=2") {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, strings.HasSuffix(gw.Lines()[0], "[INFO]  async: text to output"))
+	assert.Len(t, gw.Lines(), 3)
+	assert.Contains(t, strings.Join(gw.Lines(), "\n"), "[INFO]  async: queued")
+
+	assert.NoError(t, Flush(context.Background()))
+	assert.NoError(t, l.Close())
+	assert.True(t, gw.closed)
+}
@@ -0,0 +1,93 @@
+package hcl
+
+import (
+	"io"
+	gologger "log"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// hclogAdapter is the hclog.Logger embedded in Logger
+// the hclog logger writes all levels since hcl checks them:
+// the adapter passes the calls of users of the embedded hclog.Logger
+// through hcl, so its level, redaction and sinks apply to them as well
+type hclogAdapter struct {
+	// raw writes the lines of hcl without checking the level
+	raw hclog.Logger
+	hcl *Logger
+}
+
+// backend returns the hclog logger writing the lines of l without checking the level
+func (l Logger) backend() hclog.Logger {
+	if a, ok := l.Logger.(*hclogAdapter); ok {
+		return a.raw
+	}
+	return l.Logger
+}
+
+// setBackend sets the hclog logger writing the lines of l
+func (l *Logger) setBackend(raw hclog.Logger) {
+	l.Logger = &hclogAdapter{raw: raw, hcl: l}
+}
+
+func (a *hclogAdapter) Log(level hclog.Level, msg string, args ...interface{}) {
+	a.hcl.Log(level, msg, args...)
+}
+
+func (a *hclogAdapter) Trace(msg string, args ...interface{}) {
+	a.hcl.Log(hclog.Trace, msg, args...)
+}
+
+func (a *hclogAdapter) Debug(msg string, args ...interface{}) {
+	a.hcl.Log(hclog.Debug, msg, args...)
+}
+
+func (a *hclogAdapter) Info(msg string, args ...interface{}) {
+	a.hcl.Log(hclog.Info, msg, args...)
+}
+
+func (a *hclogAdapter) Warn(msg string, args ...interface{}) {
+	a.hcl.Log(hclog.Warn, msg, args...)
+}
+
+func (a *hclogAdapter) Error(msg string, args ...interface{}) {
+	a.hcl.Log(hclog.Error, msg, args...)
+}
+
+func (a *hclogAdapter) IsTrace() bool { return a.hcl.IsTrace() }
+func (a *hclogAdapter) IsDebug() bool { return a.hcl.IsDebug() }
+func (a *hclogAdapter) IsInfo() bool  { return a.hcl.IsInfo() }
+func (a *hclogAdapter) IsWarn() bool  { return a.hcl.IsWarn() }
+func (a *hclogAdapter) IsError() bool { return a.hcl.IsError() }
+
+func (a *hclogAdapter) ImpliedArgs() []interface{} {
+	return a.raw.ImpliedArgs()
+}
+
+func (a *hclogAdapter) With(args ...interface{}) hclog.Logger {
+	return a.hcl.With(args...).Logger
+}
+
+func (a *hclogAdapter) Name() string {
+	return a.raw.Name()
+}
+
+func (a *hclogAdapter) Named(name string) hclog.Logger {
+	return a.hcl.Named(name).Logger
+}
+
+func (a *hclogAdapter) ResetNamed(name string) hclog.Logger {
+	return a.hcl.ResetNamed(name).Logger
+}
+
+func (a *hclogAdapter) SetLevel(level hclog.Level) {
+	a.hcl.SetLevel(level)
+}
+
+func (a *hclogAdapter) StandardLogger(opts *hclog.StandardLoggerOptions) *gologger.Logger {
+	return a.hcl.StandardLogger(opts)
+}
+
+func (a *hclogAdapter) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	return a.hcl.StandardWriter(opts)
+}
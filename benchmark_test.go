@@ -1,6 +1,8 @@
 package hcl_test
 
 import (
+	"context"
+	"io"
 	"log"
 	"os"
 	"testing"
@@ -50,6 +52,163 @@ func BenchmarkHclVar(b *testing.B) {
 	}
 }
 
+func BenchmarkHclAsync(b *testing.B) {
+	f, _ := os.Create("temp")
+	b.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+
+	hcl := hcl.New(hcl.WithWriter(f), hcl.WithAsync(hcl.AsyncOpts{}))
+	// cleanups run last in first: the logger is flushed before the file is closed
+	b.Cleanup(func() { hcl.Close() })
+	for n := 0; n < b.N; n++ {
+		hcl.Warn("hcl")
+	}
+	hcl.Flush(context.Background())
+}
+
+func BenchmarkHclAsyncF(b *testing.B) {
+	f, _ := os.Create("temp")
+	b.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+
+	hcl := hcl.New(hcl.WithWriter(f), hcl.WithAsync(hcl.AsyncOpts{}))
+	b.Cleanup(func() { hcl.Close() })
+	for n := 0; n < b.N; n++ {
+		hcl.Warnf("hcl %d", n)
+	}
+	hcl.Flush(context.Background())
+}
+
+func BenchmarkHclAsyncVar(b *testing.B) {
+	f, _ := os.Create("temp")
+	b.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+
+	hcl := hcl.New(hcl.WithWriter(f), hcl.WithAsync(hcl.AsyncOpts{}))
+	b.Cleanup(func() { hcl.Close() })
+	for n := 0; n < b.N; n++ {
+		hcl.Warn("hcl", "count", n)
+	}
+	hcl.Flush(context.Background())
+}
+
+func BenchmarkHclAsyncDrop(b *testing.B) {
+	f, _ := os.Create("temp")
+	b.Cleanup(func() {
+		f.Close()
+		os.Remove(f.Name())
+	})
+
+	hcl := hcl.New(hcl.WithWriter(f), hcl.WithAsync(hcl.AsyncOpts{Policy: hcl.OverflowDropNewest}))
+	b.Cleanup(func() { hcl.Close() })
+	for n := 0; n < b.N; n++ {
+		hcl.Warn("hcl", "count", n)
+	}
+	hcl.Flush(context.Background())
+}
+
+// disabled levels should cost close to nothing
+
+func newDisabled() hcl.Logger {
+	return hcl.New(hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Off), hcl.WithStdlib(false))
+}
+
+func BenchmarkHclDisabledErrorf(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Errorf("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledWarnf(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Warnf("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledInfof(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Infof("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledDebugf(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Debugf("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledTracef(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Tracef("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledPrintf(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Printf("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledPrint(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Print("hcl", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledPrintln(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Println("hcl", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledPackageDebugf(b *testing.B) {
+	newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Debugf("hcl %d %s", n, "str")
+	}
+}
+
+func BenchmarkHclDisabledDebugVar(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		hcl.Debug("hcl", "count", n)
+	}
+}
+
+func BenchmarkHclDisabledDebugVarGuarded(b *testing.B) {
+	hcl := newDisabled()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if hcl.IsDebug() {
+			hcl.Debug("hcl", "count", n)
+		}
+	}
+}
+
 func BenchmarkDepHclog(b *testing.B) {
 
 	f, _ := os.Create("temp")
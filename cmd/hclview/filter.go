@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vogtp/go-hcl"
+)
+
+// filter selects the entries written
+type filter struct {
+	// level is the lowest level written
+	level hclog.Level
+	// names are the logger names, any of them matches the name and its sub-loggers
+	names []string
+	// since and until limit the time, entries without time do not match
+	since, until time.Time
+	// match are key=value pairs, all of them must match
+	match []field
+}
+
+// active reports if the filter drops anything
+func (f *filter) active() bool {
+	return f.level > hclog.Trace || len(f.names) > 0 || !f.since.IsZero() || !f.until.IsZero() || len(f.match) > 0
+}
+
+// accepts reports if e is written
+func (f *filter) accepts(e *entry) bool {
+	if e.Level < f.level {
+		return false
+	}
+	if len(f.names) > 0 {
+		found := false
+		for _, n := range f.names {
+			if e.Name == n || strings.HasPrefix(e.Name, n+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.since.IsZero() && (e.Time.IsZero() || e.Time.Before(f.since)) {
+		return false
+	}
+	if !f.until.IsZero() && (e.Time.IsZero() || e.Time.After(f.until)) {
+		return false
+	}
+	for _, m := range f.match {
+		if v, ok := e.value(m.Key); !ok || v != m.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// parseLevel parses the -level flag
+func parseLevel(s string) (hclog.Level, error) {
+	lvl := hclog.LevelFromString(s)
+	if lvl == hclog.NoLevel || lvl == hclog.Off {
+		return lvl, fmt.Errorf("invalid level %q", s)
+	}
+	return lvl, nil
+}
+
+// parseMatch parses a -match flag: key=value
+func parseMatch(s string) (field, error) {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return field{}, fmt.Errorf("invalid match %q: want key=value", s)
+	}
+	return field{Key: k, Value: v}, nil
+}
+
+// sinceLayouts are the time formats of -since and -until
+var sinceLayouts = []string{time.RFC3339Nano, hcl.TimeFormat, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// parseSince parses a -since or -until flag:
+// a time or a duration before now (e.g. 10m)
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range sinceLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: want a duration (10m) or a time (2006-01-02 15:04:05)", s)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// follower reads the lines of a file like tail -f
+// it reopens the file if it is rotated (renamed and recreated) or truncated
+type follower struct {
+	path string
+	poll time.Duration
+
+	f      *os.File
+	r      *bufio.Reader
+	offset int64
+	// partial is an incomplete last line
+	partial []byte
+}
+
+func newFollower(path string, poll time.Duration) (*follower, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &follower{path: path, poll: poll, f: f, r: bufio.NewReader(f)}, nil
+}
+
+// run sends the lines to lines until ctx is done
+func (fl *follower) run(ctx context.Context, lines func(string)) error {
+	defer func() { fl.f.Close() }()
+	for {
+		if err := fl.drain(lines); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(fl.poll):
+		}
+		if err := fl.checkRotated(lines); err != nil {
+			return err
+		}
+	}
+}
+
+// drain reads the complete lines up to the end of the file
+func (fl *follower) drain(lines func(string)) error {
+	for {
+		b, err := fl.r.ReadBytes('\n')
+		fl.offset += int64(len(b))
+		if err == nil {
+			if len(fl.partial) > 0 {
+				b = append(fl.partial, b...)
+				fl.partial = nil
+			}
+			lines(string(b))
+			continue
+		}
+		fl.partial = append(fl.partial, b...)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+}
+
+// checkRotated reopens the file if the path points to a new file
+// or seeks to the start if it was truncated
+func (fl *follower) checkRotated(lines func(string)) error {
+	fi, err := os.Stat(fl.path)
+	if err != nil {
+		// the file is being rotated: keep the old one until the new one shows up
+		return nil
+	}
+	cur, err := fl.f.Stat()
+	if err != nil {
+		return err
+	}
+	if os.SameFile(fi, cur) {
+		if fi.Size() < fl.offset {
+			// truncated (copytruncate)
+			if _, err := fl.f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			fl.r.Reset(fl.f)
+			fl.offset, fl.partial = 0, nil
+		}
+		return nil
+	}
+	// read what was written to the old file before the rotation
+	if err := fl.drain(lines); err != nil {
+		return err
+	}
+	if len(fl.partial) > 0 {
+		lines(string(fl.partial))
+	}
+	f, err := os.Open(fl.path)
+	if err != nil {
+		return err
+	}
+	fl.f.Close()
+	fl.f, fl.offset, fl.partial = f, 0, nil
+	fl.r.Reset(f)
+	return nil
+}
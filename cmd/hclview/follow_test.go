@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineCollector collects the lines of a follower
+type lineCollector struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (c *lineCollector) add(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, strings.TrimSpace(line))
+}
+
+func (c *lineCollector) wait(t *testing.T, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		lines := append([]string(nil), c.lines...)
+		c.mu.Unlock()
+		if len(lines) >= n {
+			return lines
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d lines", n)
+	return nil
+}
+
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(s)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestFollowRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "1\n2")
+	fl, err := newFollower(path, 5*time.Millisecond)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	var c lineCollector
+	done := make(chan error)
+	go func() { done <- fl.run(ctx, c.add) }()
+
+	assert.Equal(t, []string{"1"}, c.wait(t, 1))
+	// the partial line is completed
+	appendFile(t, path, "\n3\n")
+	assert.Equal(t, []string{"1", "2", "3"}, c.wait(t, 3))
+
+	// rotation: lines written to the old file before the new one is read are kept
+	assert.NoError(t, os.Rename(path, path+".1"))
+	appendFile(t, path+".1", "4\n")
+	appendFile(t, path, "5\n")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, c.wait(t, 5))
+
+	// truncation
+	assert.NoError(t, os.Truncate(path, 0))
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "6\n")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, c.wait(t, 6))
+
+	cancel()
+	assert.NoError(t, <-done)
+}
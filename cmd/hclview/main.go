@@ -0,0 +1,263 @@
+// Command hclview reads hcl and hclog logs, filters, colorizes and converts them
+//
+// It reads text lines (2006/01/02 15:04:05 [LEVEL] name: msg: k=v),
+// JSON lines (hcl, ECS and hclog JSON) and logfmt lines
+// from the files given or stdin:
+//
+//	hclview -level warn -name myapp.web -since 1h app.log
+//	hclview -match user=joe -format json < app.log
+//	hclview -f -color always /var/log/app.log
+//
+// Lines which are not log entries (e.g. stack traces) are written
+// after the entry they follow if it is written.
+//
+// With -f the files are read and then followed like tail -f,
+// also when they are rotated or truncated.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/go-hclog"
+	"github.com/vogtp/go-hcl"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// listFlag collects the values of a repeated flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// run executes hclview and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("hclview", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: hclview [flags] [file...]\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	level := fs.String("level", "trace", "lowest `level` written: trace, debug, info, warn or error")
+	var names, matches listFlag
+	fs.Var(&names, "name", "write the logger `name` and its sub-loggers name.* (repeatable)")
+	fs.Var(&matches, "match", "write the entries with the field `key=value` (repeatable, all must match)")
+	since := fs.String("since", "", "write the entries from `time` (2006-01-02 15:04:05, RFC 3339 or a duration like 10m)")
+	until := fs.String("until", "", "write the entries up to `time`")
+	format := fs.String("format", "text", "output `format`: text, json, logfmt or ecs")
+	colorFlag := fs.String("color", "auto", "colorize text output: auto, always or never")
+	follow := fs.Bool("f", false, "follow the files like tail -f")
+	poll := fs.Duration("poll", 250*time.Millisecond, "poll `interval` of -f")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	f, err := newFilter(*level, names, matches, *since, *until, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "hclview: %v\n", err)
+		return 2
+	}
+	outFormat, err := hcl.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(stderr, "hclview: %v\n", err)
+		return 2
+	}
+	colorOpt, err := colorOption(*colorFlag, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "hclview: %v\n", err)
+		return 2
+	}
+	v := &viewer{
+		filter: f,
+		out:    stdout,
+		sink:   hcl.NewWriterSink(stdout, hcl.WriterSinkOpts{Format: outFormat, Color: colorOpt}),
+	}
+
+	files := fs.Args()
+	if len(files) < 1 {
+		files = []string{"-"}
+	}
+	if *follow {
+		return v.follow(ctx, files, stdin, stderr, *poll)
+	}
+	code := 0
+	for _, name := range files {
+		if err := v.readFile(name, stdin); err != nil {
+			fmt.Fprintf(stderr, "hclview: %v\n", err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// newFilter creates the filter of the flags
+func newFilter(level string, names, matches []string, since, until string, now time.Time) (*filter, error) {
+	f := &filter{names: names}
+	var err error
+	if f.level, err = parseLevel(level); err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		kv, err := parseMatch(m)
+		if err != nil {
+			return nil, err
+		}
+		f.match = append(f.match, kv)
+	}
+	if since != "" {
+		if f.since, err = parseSince(since, now); err != nil {
+			return nil, err
+		}
+	}
+	if until != "" {
+		if f.until, err = parseSince(until, now); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// colorOption returns the color option of the -color flag
+func colorOption(s string, w io.Writer) (hclog.ColorOption, error) {
+	switch strings.ToLower(s) {
+	case "auto":
+		// hclog detects terminals only on files
+		if _, ok := w.(*os.File); ok {
+			return hclog.AutoColor, nil
+		}
+		return hclog.ColorOff, nil
+	case "always":
+		// fatih/color used by hclog disables colors if stdout is not a terminal
+		color.NoColor = false
+		return hclog.ForceColor, nil
+	case "never":
+		return hclog.ColorOff, nil
+	}
+	return hclog.ColorOff, fmt.Errorf("invalid color %q: want auto, always or never", s)
+}
+
+// viewer writes the entries accepted by the filter
+type viewer struct {
+	filter *filter
+	sink   *hcl.WriterSink
+	out    io.Writer
+
+	// mu serializes the lines of followed files
+	mu sync.Mutex
+}
+
+// source is the state of an input
+type source struct {
+	// written is set if the last entry was written
+	written bool
+}
+
+func (v *viewer) newSource() *source {
+	return &source{written: !v.filter.active()}
+}
+
+// line handles a line of src
+func (v *viewer) line(src *source, line string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	e, ok := parseLine(line)
+	if !ok {
+		// continuation of the last entry
+		if src.written {
+			io.WriteString(v.out, strings.TrimRight(line, "\r\n")+"\n")
+		}
+		return
+	}
+	src.written = v.filter.accepts(&e)
+	if src.written {
+		v.sink.AcceptAt(e.Time, e.Name, e.Level, e.Msg, e.args()...)
+	}
+}
+
+// readFile writes the lines of a file, - is stdin
+func (v *viewer) readFile(name string, stdin io.Reader) error {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return v.read(r)
+}
+
+// read writes the lines of r
+func (v *viewer) read(r io.Reader) error {
+	src := v.newSource()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		v.line(src, sc.Text())
+	}
+	return sc.Err()
+}
+
+// follow reads and follows the files until ctx is done
+func (v *viewer) follow(ctx context.Context, files []string, stdin io.Reader, stderr io.Writer, poll time.Duration) int {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		code int
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(stderr, "hclview: %v\n", err)
+		code = 1
+	}
+	for _, name := range files {
+		if name == "-" {
+			// stdin cannot be reopened: read it to the end
+			if err := v.read(stdin); err != nil {
+				fail(err)
+			}
+			continue
+		}
+		fl, err := newFollower(name, poll)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			src := v.newSource()
+			if err := fl.run(ctx, func(line string) { v.line(src, line) }); err != nil {
+				fail(err)
+			}
+		}()
+	}
+	wg.Wait()
+	return code
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+const input = `2022/02/25 09:40:12 [INFO]  app: started: port=8080
+2022/02/25 09:40:13 [WARN]  app.web: slow request: req=r-1 user=joe
+2022/02/25 09:40:14 [ERROR] app.db: failed: err="connection refused"
+goroutine 1 [running]:
+main.main()
+{"time":"2022-02-25T09:40:15Z","level":"debug","logger":"app.web","msg":"json line","user":"joe"}
+`
+
+func view(t *testing.T, args ...string) (string, string, int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(input), &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func TestRunFilter(t *testing.T) {
+	out, _, code := view(t)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `2022/02/25 09:40:12 [INFO]  app: started: port=8080
+2022/02/25 09:40:13 [WARN]  app.web: slow request: req=r-1 user=joe
+2022/02/25 09:40:14 [ERROR] app.db: failed: err="connection refused"
+goroutine 1 [running]:
+main.main()
+2022/02/25 09:40:15 [DEBUG] app.web: json line: user=joe
+`, out)
+
+	out, _, _ = view(t, "-level", "warn")
+	assert.Equal(t, `2022/02/25 09:40:13 [WARN]  app.web: slow request: req=r-1 user=joe
+2022/02/25 09:40:14 [ERROR] app.db: failed: err="connection refused"
+goroutine 1 [running]:
+main.main()
+`, out)
+
+	out, _, _ = view(t, "-name", "app.web", "-match", "user=joe", "-level", "debug")
+	assert.Equal(t, `2022/02/25 09:40:13 [WARN]  app.web: slow request: req=r-1 user=joe
+2022/02/25 09:40:15 [DEBUG] app.web: json line: user=joe
+`, out)
+
+	out, _, _ = view(t, "-since", "2022-02-25 09:40:13", "-until", "2022/02/25 09:40:13")
+	assert.Equal(t, "2022/02/25 09:40:13 [WARN]  app.web: slow request: req=r-1 user=joe\n", out)
+
+	out, _, _ = view(t, "-match", "port=8080", "-format", "logfmt")
+	assert.Equal(t, "time=2022-02-25T09:40:12.000Z level=info logger=app msg=started port=8080\n", out)
+
+	out, _, _ = view(t, "-name", "app.db", "-format", "json")
+	assert.Equal(t, `{"time":"2022-02-25T09:40:14.000000Z","level":"error","logger":"app.db","msg":"failed","err":"connection refused"}
+goroutine 1 [running]:
+main.main()
+`, out)
+
+	out, _, _ = view(t, "-level", "error", "-color", "always")
+	assert.Contains(t, out, "\x1b[")
+}
+
+func TestFilterName(t *testing.T) {
+	f := &filter{names: []string{"myapp"}}
+	for name, want := range map[string]bool{
+		"myapp":       true,
+		"myapp.web":   true,
+		"myapp.web.a": true,
+		"myapp2":      false,
+		"myappweb":    false,
+		"other":       false,
+		"":            false,
+	} {
+		assert.Equal(t, want, f.accepts(&entry{Name: name}), name)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-level", "loud"},
+		{"-match", "nokey"},
+		{"-since", "yesterday"},
+		{"-format", "xml"},
+		{"-color", "pink"},
+		{"-unknown"},
+	} {
+		_, errOut, code := view(t, args...)
+		assert.Equal(t, 2, code, args)
+		assert.NotEmpty(t, errOut, args)
+	}
+	_, errOut, code := view(t, filepath.Join(t.TempDir(), "missing.log"))
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "missing.log")
+}
+
+func TestRunFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("[INFO]  app: first\n[DEBUG] app: hidden\n"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan int)
+	go func() { done <- run(ctx, []string{"-f", "-poll", "5ms", "-level", "info", path}, nil, &out, &out) }()
+	appendFile(t, path, "[WARN]  app: second\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "second") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	assert.Equal(t, "[INFO]  app: first\n[WARN]  app: second\n", out.String())
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2022, 2, 25, 9, 40, 12, 0, time.Local)
+	since, err := parseSince("10m", now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), since)
+	since, err = parseSince("2022-02-25", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 2, 25, 0, 0, 0, 0, time.Local), since)
+	lvl, err := parseLevel("WARN")
+	assert.NoError(t, err)
+	assert.Equal(t, hclog.Warn, lvl)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vogtp/go-hcl"
+)
+
+// field is a key/value pair of an entry
+type field struct {
+	Key   string
+	Value interface{}
+}
+
+// entry is a parsed log line
+type entry struct {
+	// Time is zero if the line has no time
+	Time   time.Time
+	Level  hclog.Level
+	Name   string
+	Msg    string
+	Fields []field
+}
+
+// value returns the value of key formatted as string
+func (e *entry) value(key string) (string, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return fmt.Sprint(f.Value), true
+		}
+	}
+	return "", false
+}
+
+// args returns the fields as key/value pairs
+func (e *entry) args() []interface{} {
+	args := make([]interface{}, 0, 2*len(e.Fields))
+	for _, f := range e.Fields {
+		args = append(args, f.Key, f.Value)
+	}
+	return args
+}
+
+// textLine matches hclog text lines: TIME [LEVEL] rest
+var textLine = regexp.MustCompile(`^(?:(.*?) )?\[(TRACE|DEBUG|INFO|WARN|ERROR)\] +(.*)$`)
+
+// textName matches the logger name in front of the message
+var textName = regexp.MustCompile(`^([^\s:]+): (.*)$`)
+
+// timeLayouts are the time formats of text lines
+var timeLayouts = []string{hcl.TimeFormat, "2006-01-02T15:04:05.000Z0700", time.RFC3339Nano}
+
+// parseLine parses a text, JSON or logfmt line
+// ok is false if the line is not a log entry (e.g. a continuation line)
+func parseLine(line string) (e entry, ok bool) {
+	line = strings.TrimRight(line, "\r\n")
+	switch {
+	case strings.HasPrefix(line, "{"):
+		return parseJSON(line)
+	case strings.HasPrefix(line, "time=") || strings.HasPrefix(line, "level="):
+		return parseLogfmt(line)
+	}
+	return parseText(line)
+}
+
+// parseText parses 2006/01/02 15:04:05 [LEVEL] name: msg: k=v
+func parseText(line string) (entry, bool) {
+	m := textLine.FindStringSubmatch(line)
+	if m == nil {
+		return entry{}, false
+	}
+	e := entry{Time: parseTime(m[1]), Level: hclog.LevelFromString(m[2])}
+	rest := m[3]
+	if n := textName.FindStringSubmatch(rest); n != nil {
+		e.Name, rest = n[1], n[2]
+	}
+	e.Msg = rest
+	// the fields follow the first ": " after which only pairs follow
+	for i := strings.Index(rest, ": "); i >= 0; {
+		if fields, ok := parsePairs(rest[i+2:]); ok && len(fields) > 0 {
+			e.Msg, e.Fields = rest[:i], fields
+			break
+		}
+		next := strings.Index(rest[i+2:], ": ")
+		if next < 0 {
+			break
+		}
+		i += 2 + next
+	}
+	return e, true
+}
+
+// parseTime parses the time of a text line, zero if it has none
+func parseTime(s string) time.Time {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseLogfmt parses time=... level=... logger=... msg=... k=v
+func parseLogfmt(line string) (entry, bool) {
+	pairs, ok := parsePairs(line)
+	if !ok {
+		return entry{}, false
+	}
+	e := entry{Level: hclog.NoLevel}
+	for _, p := range pairs {
+		s := fmt.Sprint(p.Value)
+		switch p.Key {
+		case "time":
+			e.Time, _ = time.Parse(hcl.LogfmtTimeFormat, s)
+		case "level":
+			e.Level = hclog.LevelFromString(s)
+		case "logger":
+			e.Name = s
+		case "msg":
+			e.Msg = s
+		default:
+			e.Fields = append(e.Fields, field{Key: strings.TrimPrefix(p.Key, "arg_"), Value: p.Value})
+		}
+	}
+	return e, e.Level != hclog.NoLevel
+}
+
+// parsePairs parses space separated k=v pairs
+// values are bare, quoted ("a b") or bracketed ([a b])
+func parsePairs(s string) ([]field, bool) {
+	var fields []field
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return fields, true
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq < 1 || strings.ContainsAny(s[:eq], " \"") {
+			return nil, false
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+		var v interface{}
+		switch {
+		case strings.HasPrefix(s, `"`):
+			end := quotedEnd(s)
+			if end < 0 {
+				return nil, false
+			}
+			q, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return nil, false
+			}
+			v, s = q, s[end:]
+		case strings.HasPrefix(s, "["):
+			end := bracketEnd(s)
+			if end < 0 {
+				return nil, false
+			}
+			v, s = listValue(s[:end]), s[end:]
+		default:
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			v, s = bareValue(s[:end]), s[end:]
+		}
+		if s != "" && s[0] != ' ' {
+			return nil, false
+		}
+		fields = append(fields, field{Key: key, Value: v})
+	}
+}
+
+// bareValue converts unquoted numbers and booleans
+func bareValue(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		return b
+	}
+	return s
+}
+
+// listValue converts ["a", "b"] to a slice of strings
+// other lists are kept as string
+func listValue(s string) interface{} {
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return []string{}
+	}
+	var items []string
+	for inner != "" {
+		end := quotedEnd(inner)
+		if !strings.HasPrefix(inner, `"`) || end < 0 {
+			return s
+		}
+		item, err := strconv.Unquote(inner[:end])
+		if err != nil {
+			return s
+		}
+		items = append(items, item)
+		inner = strings.TrimPrefix(strings.TrimLeft(inner[end:], " "), ",")
+		inner = strings.TrimLeft(inner, " ")
+	}
+	return items
+}
+
+// quotedEnd returns the end of the quoted string at the start of s or -1
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// bracketEnd returns the end of the bracketed value at the start of s or -1
+func bracketEnd(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			end := quotedEnd(s[i:])
+			if end < 0 {
+				return -1
+			}
+			i += end - 1
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// jsonKeys are the keys of time, level, name and message
+// of hcl JSON, hcl ECS and hclog JSON lines
+var jsonKeys = map[string]string{
+	"time":        "time",
+	"@timestamp":  "time",
+	"level":       "level",
+	"@level":      "level",
+	"log.level":   "level",
+	"logger":      "name",
+	"@module":     "name",
+	"log.logger":  "name",
+	"msg":         "msg",
+	"@message":    "msg",
+	"message":     "msg",
+	"ecs.version": "",
+}
+
+// parseJSON parses a JSON line keeping the order of the fields
+func parseJSON(line string) (entry, bool) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return entry{}, false
+	}
+	e := entry{Level: hclog.NoLevel}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return entry{}, false
+		}
+		key, _ := t.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return entry{}, false
+		}
+		role, known := jsonKeys[key]
+		s, isString := v.(string)
+		switch {
+		case known && role == "":
+		case role == "time" && isString:
+			e.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case role == "level" && isString:
+			e.Level = hclog.LevelFromString(s)
+		case role == "name" && isString:
+			e.Name = s
+		case role == "msg" && isString:
+			e.Msg = s
+		default:
+			e.Fields = append(e.Fields, field{Key: strings.TrimPrefix(key, "arg_"), Value: jsonValue(v)})
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return entry{}, false
+	}
+	return e, e.Level != hclog.NoLevel
+}
+
+// jsonValue converts numbers to int64 or float64 and objects to jsonObject
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]interface{}:
+		return jsonObject(val)
+	}
+	return v
+}
+
+// jsonObject is an object value of a JSON line
+// it is written as JSON in text lines
+type jsonObject map[string]interface{}
+
+// String returns the message of errors written by hcl ({"message":..., "type":...})
+// and the JSON of other objects
+func (o jsonObject) String() string {
+	if msg, ok := o["message"].(string); ok && len(o) == 2 {
+		if _, ok := o["type"]; ok {
+			return msg
+		}
+	}
+	b, err := json.Marshal(map[string]interface{}(o))
+	if err != nil {
+		return fmt.Sprint(map[string]interface{}(o))
+	}
+	return string(b)
+}
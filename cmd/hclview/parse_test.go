@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseText(t *testing.T) {
+	e, ok := parseLine(`2022/02/25 09:40:12 [ERROR] app.web: cannot serve: /a: err="connection refused" code=500 ok=false tags=["a", "b"]`)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 2, 25, 9, 40, 12, 0, time.Local), e.Time)
+	assert.Equal(t, hclog.Error, e.Level)
+	assert.Equal(t, "app.web", e.Name)
+	assert.Equal(t, "cannot serve: /a", e.Msg)
+	assert.Equal(t, []field{
+		{Key: "err", Value: "connection refused"},
+		{Key: "code", Value: int64(500)},
+		{Key: "ok", Value: false},
+		{Key: "tags", Value: []string{"a", "b"}},
+	}, e.Fields)
+
+	e, ok = parseLine("[INFO]  app: no time")
+	assert.True(t, ok)
+	assert.True(t, e.Time.IsZero())
+	assert.Equal(t, entry{Level: hclog.Info, Name: "app", Msg: "no time"}, e)
+
+	_, ok = parseLine("goroutine 1 [running]:")
+	assert.False(t, ok)
+}
+
+func TestParseJSON(t *testing.T) {
+	want := entry{
+		Time:   time.Date(2022, 2, 25, 9, 40, 12, 123000000, time.UTC),
+		Level:  hclog.Warn,
+		Name:   "app",
+		Msg:    "slow",
+		Fields: []field{{Key: "ms", Value: 1500.5}, {Key: "msg", Value: "arg"}},
+	}
+	for _, line := range []string{
+		`{"time":"2022-02-25T09:40:12.123Z","level":"warn","logger":"app","msg":"slow","ms":1500.5,"arg_msg":"arg"}`,
+		`{"@timestamp":"2022-02-25T09:40:12.123Z","log.level":"warn","log.logger":"app","message":"slow","ecs.version":"1.6.0","ms":1500.5,"arg_msg":"arg"}`,
+		`{"@level":"warn","@message":"slow","@module":"app","@timestamp":"2022-02-25T09:40:12.123000Z","ms":1500.5,"arg_msg":"arg"}`,
+	} {
+		e, ok := parseLine(line)
+		assert.True(t, ok, line)
+		assert.True(t, want.Time.Equal(e.Time), line)
+		e.Time = want.Time
+		assert.Equal(t, want, e, line)
+	}
+
+	e, ok := parseLine(`{"level":"error","msg":"failed","err":{"message":"refused","type":"*net.OpError"},"n":{"a":1}}`)
+	assert.True(t, ok)
+	assert.Equal(t, "refused", e.Fields[0].Value.(jsonObject).String())
+	assert.Equal(t, `{"a":1}`, e.Fields[1].Value.(jsonObject).String())
+
+	_, ok = parseLine(`{"no":"level"}`)
+	assert.False(t, ok)
+	_, ok = parseLine(`{broken`)
+	assert.False(t, ok)
+}
+
+func TestParseLogfmt(t *testing.T) {
+	e, ok := parseLine(`time=2022-02-25T09:40:12.123Z level=info logger=app msg="a b" user=joe empty=""`)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 2, 25, 9, 40, 12, 123000000, time.UTC), e.Time)
+	assert.Equal(t, hclog.Info, e.Level)
+	assert.Equal(t, "app", e.Name)
+	assert.Equal(t, "a b", e.Msg)
+	assert.Equal(t, []field{{Key: "user", Value: "joe"}, {Key: "empty", Value: ""}}, e.Fields)
+
+	_, ok = parseLine(`level=info msg="unterminated`)
+	assert.False(t, ok)
+}
@@ -0,0 +1,163 @@
+package hcl
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+type loggerCtxKey struct{}
+
+type fieldsCtxKey struct{}
+
+// NewContext returns a context carrying the logger
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns the logger of the context (see NewContext)
+// or the default logger if the context has none
+// the fields of the context are added to the logger (see WithContext)
+func FromContext(ctx context.Context) Logger {
+	return contextLogger(ctx).WithContext(ctx)
+}
+
+// WithContext creates a sublogger with the fields of the context (see WithContextFields)
+// and the trace_id and span_id of its span (see TraceFields)
+func (l Logger) WithContext(ctx context.Context) Logger {
+	if args := contextArgs(ctx); len(args) > 0 {
+		return l.With(args...)
+	}
+	return l
+}
+
+// contextArgs returns the fields and the trace fields of the context
+func contextArgs(ctx context.Context) []interface{} {
+	fields := ContextFields(ctx)
+	trace := TraceFields(ctx)
+	if len(trace) < 1 {
+		return fields
+	}
+	args := make([]interface{}, 0, len(fields)+len(trace))
+	args = append(args, trace...)
+	return append(args, fields...)
+}
+
+// contextLogger returns the logger of the context without its fields
+func contextLogger(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+		return l
+	}
+	return *defaultLog()
+}
+
+// WithContextFields returns a context carrying the key/value pairs
+// in addition to the fields of ctx
+// the fields are added by the Ctx functions (e.g. InfoCtx) and FromContext
+func WithContextFields(ctx context.Context, args ...interface{}) context.Context {
+	parent := ContextFields(ctx)
+	fields := make([]interface{}, 0, len(parent)+len(args))
+	fields = append(fields, parent...)
+	fields = append(fields, args...)
+	return context.WithValue(ctx, fieldsCtxKey{}, fields)
+}
+
+// ContextFields returns the key/value pairs of the context (see WithContextFields)
+func ContextFields(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(fieldsCtxKey{}).([]interface{})
+	return fields
+}
+
+// logCtx logs the message with the fields of the context added to args
+func (l Logger) logCtx(ctx context.Context, level hclog.Level, msg string, args []interface{}) {
+	if fields := contextArgs(ctx); len(fields) > 0 {
+		all := make([]interface{}, 0, len(fields)+len(args))
+		all = append(all, fields...)
+		args = append(all, args...)
+	}
+	l.log(level, msg, args)
+}
+
+// LogCtx emits a message and key/value pairs at a provided log level
+// the fields and trace ids of the context are added
+func (l Logger) LogCtx(ctx context.Context, level hclog.Level, msg string, args ...interface{}) {
+	if l.enabled(level) {
+		l.logCtx(ctx, level, msg, args)
+	}
+}
+
+// TraceCtx logs a message and key/value pairs at the TRACE level
+// the fields and trace ids of the context are added
+func (l Logger) TraceCtx(ctx context.Context, msg string, args ...interface{}) {
+	if l.enabled(hclog.Trace) {
+		l.logCtx(ctx, hclog.Trace, msg, args)
+	}
+}
+
+// DebugCtx logs a message and key/value pairs at the DEBUG level
+// the fields and trace ids of the context are added
+func (l Logger) DebugCtx(ctx context.Context, msg string, args ...interface{}) {
+	if l.enabled(hclog.Debug) {
+		l.logCtx(ctx, hclog.Debug, msg, args)
+	}
+}
+
+// InfoCtx logs a message and key/value pairs at the INFO level
+// the fields and trace ids of the context are added
+func (l Logger) InfoCtx(ctx context.Context, msg string, args ...interface{}) {
+	if l.enabled(hclog.Info) {
+		l.logCtx(ctx, hclog.Info, msg, args)
+	}
+}
+
+// WarnCtx logs a message and key/value pairs at the WARN level
+// the fields and trace ids of the context are added
+func (l Logger) WarnCtx(ctx context.Context, msg string, args ...interface{}) {
+	if l.enabled(hclog.Warn) {
+		l.logCtx(ctx, hclog.Warn, msg, args)
+	}
+}
+
+// ErrorCtx logs a message and key/value pairs at the ERROR level
+// the fields and trace ids of the context are added
+func (l Logger) ErrorCtx(ctx context.Context, msg string, args ...interface{}) {
+	if l.enabled(hclog.Error) {
+		l.logCtx(ctx, hclog.Error, msg, args)
+	}
+}
+
+// LogCtx emits a message and key/value pairs at a provided log level
+// to the logger of the context with the fields of the context
+func LogCtx(ctx context.Context, level hclog.Level, msg string, args ...interface{}) {
+	contextLogger(ctx).LogCtx(ctx, level, msg, args...)
+}
+
+// TraceCtx logs a message and key/value pairs at the TRACE level
+// to the logger of the context with the fields of the context
+func TraceCtx(ctx context.Context, msg string, args ...interface{}) {
+	contextLogger(ctx).TraceCtx(ctx, msg, args...)
+}
+
+// DebugCtx logs a message and key/value pairs at the DEBUG level
+// to the logger of the context with the fields of the context
+func DebugCtx(ctx context.Context, msg string, args ...interface{}) {
+	contextLogger(ctx).DebugCtx(ctx, msg, args...)
+}
+
+// InfoCtx logs a message and key/value pairs at the INFO level
+// to the logger of the context with the fields of the context
+func InfoCtx(ctx context.Context, msg string, args ...interface{}) {
+	contextLogger(ctx).InfoCtx(ctx, msg, args...)
+}
+
+// WarnCtx logs a message and key/value pairs at the WARN level
+// to the logger of the context with the fields of the context
+func WarnCtx(ctx context.Context, msg string, args ...interface{}) {
+	contextLogger(ctx).WarnCtx(ctx, msg, args...)
+}
+
+// ErrorCtx logs a message and key/value pairs at the ERROR level
+// to the logger of the context with the fields of the context
+func ErrorCtx(ctx context.Context, msg string, args ...interface{}) {
+	contextLogger(ctx).ErrorCtx(ctx, msg, args...)
+}
@@ -0,0 +1,41 @@
+package hcl_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+)
+
+func TestContext(t *testing.T) {
+	buf := &syncBuffer{}
+	l := hcl.New(hcl.WithName("ctx"), hcl.WithWriter(buf), hcl.WithLevel(hclog.Debug), hcl.WithStdlib(false))
+
+	ctx := context.Background()
+	hcl.InfoCtx(ctx, "no fields")
+	assert.Contains(t, buf.String(), "[INFO]  ctx: no fields\n")
+
+	ctx = hcl.WithContextFields(ctx, "request", 42)
+	ctx = hcl.WithContextFields(ctx, "user", "joe")
+	hcl.WarnCtx(ctx, "default", "k", "v")
+	assert.Contains(t, buf.String(), "[WARN]  ctx: default: request=42 user=joe k=v\n")
+
+	ctx = hcl.NewContext(ctx, l.Named("web"))
+	hcl.ErrorCtx(ctx, "stored")
+	assert.Contains(t, buf.String(), "[ERROR] ctx.web: stored: request=42 user=joe\n")
+	hcl.FromContext(ctx).Debugf("from %s", "context")
+	assert.Contains(t, buf.String(), "[DEBUG] ctx.web: from context: request=42 user=joe\n")
+	hcl.TraceCtx(ctx, "disabled")
+	assert.NotContains(t, buf.String(), "disabled")
+
+	l.Slog().InfoContext(ctx, "slog", "k", "v")
+	assert.Contains(t, buf.String(), "[INFO]  ctx: slog: request=42 user=joe k=v\n")
+
+	// fields of a parent context are not changed
+	hcl.WithContextFields(ctx, "child", 1)
+	assert.Equal(t, 4, len(hcl.ContextFields(ctx)))
+	assert.False(t, strings.Contains(buf.String(), "child"))
+}
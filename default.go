@@ -0,0 +1,54 @@
+package hcl
+
+import (
+	"sync/atomic"
+)
+
+// defaultLogger holds the logger used by the package level functions.
+// It is only ever replaced as a whole by SetDefault, never modified in place:
+// only its level is shared with the loggers derived from it (see SetLevel).
+var defaultLogger atomic.Pointer[Logger]
+
+// defaultLog returns the default logger
+// a logger named after the binary is created on first use
+func defaultLog() *Logger {
+	for {
+		if l := defaultLogger.Load(); l != nil {
+			return l
+		}
+		l := newLogger()
+		stdlibMu.Lock()
+		ok := defaultLogger.CompareAndSwap(nil, l)
+		if ok {
+			l.redirectStdlib()
+		}
+		stdlibMu.Unlock()
+		if ok {
+			return l
+		}
+	}
+}
+
+// Default returns the logger used by the package level functions
+// it is a copy: use SetDefault to make changes like SetWriter effective
+//
+// It is safe to call Default concurrently with logging,
+// SetDefault and New.
+func Default() Logger {
+	return *defaultLog()
+}
+
+// SetDefault makes l the logger used by the package level functions
+// if l was created with WithStdlib(true) the stdlib log and slog are redirected to it
+//
+// The default logger is swapped atomically: it is safe to call SetDefault
+// while other goroutines log via the package level functions.
+// Every log call uses either the old or the new logger, never a mix of both.
+func SetDefault(l Logger) {
+	// the stdlib loggers are redirected together with the swap:
+	// concurrent calls cannot leave them writing to another logger
+	stdlibMu.Lock()
+	defer stdlibMu.Unlock()
+	defaultLogger.Store(&l)
+	l.redirectStdlib()
+}
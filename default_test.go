@@ -0,0 +1,112 @@
+package hcl_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	gologger "log"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSetDefault(t *testing.T) {
+	var buf syncBuffer
+	l := hcl.New(hcl.WithName("first"), hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Info))
+	assert.Equal(t, "first", hcl.Default().Name())
+
+	l2 := l.ResetNamed("second")
+	l2.SetWriter(&buf)
+	hcl.SetDefault(l2)
+	assert.Equal(t, "second", hcl.Default().Name())
+	hcl.Info("to second")
+	assert.Contains(t, buf.String(), "[INFO]  second: to second\n")
+}
+
+func TestWithDefault(t *testing.T) {
+	hcl.New(hcl.WithName("first"), hcl.WithWriter(io.Discard))
+	l := hcl.New(hcl.WithName("second"), hcl.WithWriter(io.Discard), hcl.WithDefault(false))
+	assert.Equal(t, "second", l.Name())
+	assert.Equal(t, "first", hcl.Default().Name())
+}
+
+func TestSetDefaultStdlib(t *testing.T) {
+	defer hcl.RestoreStdlib()
+	bufs := map[string]*syncBuffer{"one": {}, "two": {}}
+	loggers := make([]hcl.Logger, 0, len(bufs))
+	for name, buf := range bufs {
+		loggers = append(loggers, hcl.New(hcl.WithName(name), hcl.WithWriter(buf), hcl.WithLevel(hclog.Info), hcl.WithDefault(false)))
+	}
+	for n := 0; n < 100; n++ {
+		var wg sync.WaitGroup
+		for _, l := range loggers {
+			wg.Add(1)
+			go func(l hcl.Logger) {
+				defer wg.Done()
+				hcl.SetDefault(l)
+			}(l)
+		}
+		wg.Wait()
+		// the stdlib logger writes to the default logger
+		msg := fmt.Sprint("std ", n)
+		gologger.Print(msg)
+		assert.Contains(t, bufs[hcl.Default().Name()].String(), msg+"\n")
+	}
+}
+
+func TestDefaultStress(t *testing.T) {
+	const workers = 8
+	const loops = 200
+	hcl.New(hcl.WithWriter(io.Discard))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < loops; n++ {
+				hcl.Infof("logging %d", n)
+				hcl.Debug("logging", "n", n)
+				hcl.Named("sub").Warn("sub logging")
+				_ = hcl.IsTrace()
+				_ = hcl.Default().Name()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for n := 0; n < loops/10; n++ {
+				hcl.New(hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Debug))
+				hcl.SetDefault(hcl.Default().ResetNamed("reset"))
+				_ = hcl.LibraryLogger("lib")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for n := 0; n < loops; n++ {
+				hcl.SetLevel(hclog.Trace)
+				hcl.SetLevel(hclog.Info)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,129 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Environment variables read by New
+//
+// The precedence is: LoggerOpts given to New beat the environment,
+// which beats the go run/go test heuristics.
+const (
+	// EnvLevel sets the level: trace, debug, info, warn, error or off
+	EnvLevel = "HCL_LEVEL"
+	// EnvFormat sets the output format: text, json, logfmt or ecs (see Format)
+	EnvFormat = "HCL_FORMAT"
+	// EnvColor sets the coloring: auto, always or never
+	EnvColor = "HCL_COLOR"
+	// EnvTimeFormat sets the time format (go layout), none disables the time
+	EnvTimeFormat = "HCL_TIME_FORMAT"
+	// EnvOutput sets the output: stderr, stdout, journald or a file path (appended, see Reopener)
+	EnvOutput = "HCL_OUTPUT"
+	// EnvLevels sets the per name levels (see SetLevels)
+	// it is applied once by the first New, call SetLevels after New to override it
+	EnvLevels = "HCL_LEVELS"
+)
+
+// envLevelsOnce applies EnvLevels once: later loggers must not
+// reset the levels changed at runtime (SetNamedLevel, hcladmin, signals)
+var envLevelsOnce sync.Once
+
+// envFiles are the files opened for EnvOutput by path
+// they are shared by all loggers
+var (
+	envFilesMu sync.Mutex
+	envFiles   = map[string]*fileWriter{}
+)
+
+// applyEnv configures the settings of l not set by a LoggerOpt
+// from the environment
+// it returns the problems found in the environment
+func (l *Logger) applyEnv() []error {
+	var errs []error
+	if v := os.Getenv(EnvLevel); v != "" && l.level == hclog.NoLevel {
+		if lvl := hclog.LevelFromString(v); lvl != hclog.NoLevel {
+			l.level = lvl
+		} else {
+			errs = append(errs, fmt.Errorf("invalid %s %q", EnvLevel, v))
+		}
+	}
+	if v := os.Getenv(EnvOutput); strings.EqualFold(v, "journald") && l.w == nil {
+		if l.journald == nil {
+			l.journald = &JournaldOpts{}
+		}
+	} else if v != "" && l.w == nil {
+		w, err := envOutput(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", EnvOutput, err))
+		} else {
+			l.w = w
+			l.outputSet = true
+		}
+	}
+	if v := os.Getenv(EnvLevels); v != "" {
+		envLevelsOnce.Do(func() {
+			if err := SetLevels(v); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", EnvLevels, err))
+			}
+		})
+	}
+	if l.customOpts {
+		// the hclog options are set explicitly
+		return errs
+	}
+	if v := os.Getenv(EnvFormat); v != "" && !l.formatSet {
+		if f, err := ParseFormat(v); err == nil {
+			l.format = f
+		} else {
+			errs = append(errs, fmt.Errorf("invalid %s %q", EnvFormat, v))
+		}
+	}
+	if v := os.Getenv(EnvColor); v != "" {
+		switch strings.ToLower(v) {
+		case "auto":
+			l.hcOpts.Color = hclog.AutoColor
+		case "always", "on", "true", "1":
+			l.hcOpts.Color = hclog.ForceColor
+		case "never", "off", "false", "0":
+			l.hcOpts.Color = hclog.ColorOff
+		default:
+			errs = append(errs, fmt.Errorf("invalid %s %q", EnvColor, v))
+		}
+	}
+	if v := os.Getenv(EnvTimeFormat); v != "" {
+		if strings.ToLower(v) == "none" {
+			l.hcOpts.DisableTime = true
+		} else {
+			l.hcOpts.TimeFormat = v
+		}
+	}
+	return errs
+}
+
+// envOutput opens the output given by the environment
+func envOutput(v string) (io.Writer, error) {
+	switch strings.ToLower(v) {
+	case "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+	// the file is opened once and kept open for the lifetime of the process
+	envFilesMu.Lock()
+	defer envFilesMu.Unlock()
+	if f, ok := envFiles[v]; ok && !f.closed() {
+		return f, nil
+	}
+	f, err := openFile(v, RotateOpts{})
+	if err != nil {
+		return nil, err
+	}
+	envFiles[v] = f
+	return f, nil
+}
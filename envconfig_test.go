@@ -0,0 +1,97 @@
+package hcl
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// keepDefault restores the default logger when the test finishes
+func keepDefault(t *testing.T) {
+	l := defaultLogger.Load()
+	t.Cleanup(func() { defaultLogger.Store(l) })
+}
+
+func TestEnvLevel(t *testing.T) {
+	keepDefault(t)
+	t.Setenv(EnvLevel, "trace")
+	l := New(WithWriter(&buf))
+	assert.Equal(t, hclog.Trace, l.GetLevel())
+
+	// options beat the environment
+	l = New(WithWriter(&buf), WithLevel(hclog.Error))
+	assert.Equal(t, hclog.Error, l.GetLevel())
+
+	// invalid values fall back to the heuristics
+	t.Setenv(EnvLevel, "loud")
+	l = New(WithName("env"), WithWriter(&buf))
+	assert.Equal(t, hclog.Debug, l.GetLevel())
+	assert.Equal(t, "[WARN]  env: ignoring environment: invalid HCL_LEVEL \"loud\"\n", buf.Line())
+}
+
+func TestEnvOutput(t *testing.T) {
+	keepDefault(t)
+	file := filepath.Join(t.TempDir(), "env.log")
+	t.Setenv(EnvOutput, file)
+	t.Setenv(EnvTimeFormat, "none")
+	l := New(WithName("env"), WithStdlib(false))
+	l.Info("text to output")
+	out, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "[INFO]  env: text to output\n", string(out))
+
+	// options beat the environment
+	New(WithName("env"), WithWriter(&buf), WithStdlib(false)).Info("text to output")
+	assert.Equal(t, "[INFO]  env: text to output\n", buf.String())
+	buf.Reset()
+
+	// the file is opened once
+	l2 := New(WithName("env2"), WithStdlib(false))
+	assert.Same(t, l.w, l2.w)
+
+	t.Setenv(EnvOutput, filepath.Join(file, "not-a-dir", "env.log"))
+	l = New(WithName("env"), WithStdlib(false))
+	assert.Equal(t, os.Stderr, l.w)
+	// an invalid output does not count as given: the journal is still detected
+	assert.False(t, l.outputSet)
+}
+
+func TestEnvFormat(t *testing.T) {
+	keepDefault(t)
+	var out bytes.Buffer
+	t.Setenv(EnvFormat, "json")
+	New(WithName("env"), WithWriter(&out), WithLevel(hclog.Info)).Info("text to output", "key", 42)
+	m := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &m))
+	assert.Equal(t, "env", m["logger"])
+	assert.Equal(t, "text to output", m["msg"])
+	assert.Equal(t, float64(42), m["key"])
+
+	// explicit hclog options beat the environment
+	out.Reset()
+	opts := hclog.LoggerOptions{DisableTime: true}
+	New(WithName("env"), WithWriter(&out), WithLevel(hclog.Info), WithLoggerOptions(&opts)).Info("text to output")
+	assert.Equal(t, "[INFO]  env: text to output\n", out.String())
+}
+
+func TestEnvLevels(t *testing.T) {
+	keepDefault(t)
+	defer SetLevels("")
+	envLevelsOnce = sync.Once{}
+	t.Cleanup(func() { envLevelsOnce = sync.Once{} })
+	t.Setenv(EnvLevels, "env=error,env.web=trace")
+	l := New(WithName("env"), WithWriter(&buf), WithLevel(hclog.Info))
+	assert.Equal(t, hclog.Error, l.GetLevel())
+	assert.Equal(t, hclog.Trace, l.Named("web").GetLevel())
+
+	// runtime changes are kept by later loggers
+	SetNamedLevel("env", hclog.Warn)
+	New(WithName("other"), WithWriter(&buf), WithLevel(hclog.Info))
+	assert.Equal(t, hclog.Warn, l.GetLevel())
+}
@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"fmt"
 	"math"
 	"os"
 	"strconv"
@@ -56,6 +57,26 @@ func (Logger) IsGoTest() bool {
 	return IsGoTest()
 }
 
+// IsJournald checks if stderr is connected to systemd-journald
+// it does this by comparing JOURNAL_STREAM with stderr
+func IsJournald() bool {
+	v := os.Getenv("JOURNAL_STREAM")
+	if v == "" {
+		return false
+	}
+	dev, ino, ok := stderrID()
+	if !ok {
+		return false
+	}
+	return v == fmt.Sprintf("%d:%d", dev, ino)
+}
+
+// IsJournald checks if stderr is connected to systemd-journald
+// it does this by comparing JOURNAL_STREAM with stderr
+func (Logger) IsJournald() bool {
+	return IsJournald()
+}
+
 // GetExecutableName extracts the name of the executable
 // removes path and suffix
 func GetExecutableName() string {
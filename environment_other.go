@@ -0,0 +1,9 @@
+//go:build !unix
+
+package hcl
+
+// stderrID returns the device and inode of stderr
+// the platform has no journald
+func stderrID() (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,327 @@
+package hcl
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reopener is implemented by outputs which can be reopened
+// e.g. files moved away by logrotate
+type Reopener interface {
+	Reopen() error
+}
+
+// errNotReopener is returned by outputs wrapping an output which cannot be reopened
+var errNotReopener = errors.New("output cannot be reopened")
+
+// RotateOpts configures the rotation of files written by WithFile
+// the zero value never rotates
+type RotateOpts struct {
+	// MaxSize is the size in bytes after which the file is rotated
+	MaxSize int64
+	// Interval is the time after which the file is rotated
+	Interval time.Duration
+	// MaxAge is the age after which rotated files are deleted
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+	// LocalTime uses the local time instead of UTC in the names of rotated files
+	LocalTime bool
+}
+
+// backupTimeFormat is the time format of rotated file names
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// WithFile is used to create a logger writing to the file path
+// the file is rotated according to opts: rotated files are named
+// like app-2006-01-02T15-04-05.000.log
+func WithFile(path string, opts RotateOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.outputSet = true
+		f, err := openFile(path, opts)
+		if err != nil {
+			l.initErrs = append(l.initErrs, fmt.Errorf("cannot open log file: %w", err))
+			return
+		}
+		l.w = f
+	}
+}
+
+// fileWriter appends to a file which can be rotated and reopened
+// it is shared by all loggers writing to it
+type fileWriter struct {
+	path string
+	opts RotateOpts
+	now  func() time.Time
+
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time
+
+	// mill compresses and deletes rotated files in the background
+	mill     chan struct{}
+	millDone chan struct{}
+}
+
+// openFile opens path for appending
+func openFile(path string, opts RotateOpts) (*fileWriter, error) {
+	w := &fileWriter{
+		path:     path,
+		opts:     opts,
+		now:      time.Now,
+		mill:     make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	go w.runMill()
+	return w, nil
+}
+
+// open opens the file
+// w.mu must be held
+func (w *fileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = info.Size()
+	w.opened = w.now()
+	return nil
+}
+
+// Write appends p to the file
+// the file is rotated before if needed
+func (w *fileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if w.needsRotation(len(p)) {
+		// the line is written to the current file if the rotation fails
+		rotateErr = w.rotate()
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// needsRotation checks if the file has to be rotated before writing n bytes
+// w.mu must be held
+func (w *fileWriter) needsRotation(n int) bool {
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(n) > w.opts.MaxSize {
+		return true
+	}
+	if w.opts.Interval > 0 && !w.now().Before(w.opened.Add(w.opts.Interval)) {
+		if w.size == 0 {
+			// nothing to rotate: the interval starts again
+			w.opened = w.now()
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// rotate moves the file to a backup and opens a new file
+// the current file is kept if it fails
+// w.mu must be held
+func (w *fileWriter) rotate() error {
+	backup := w.backupName()
+	if err := os.Rename(w.path, backup); err != nil {
+		return err
+	}
+	old := w.f
+	if err := w.open(); err != nil {
+		// keep writing to the file at its old name
+		os.Rename(backup, w.path)
+		return err
+	}
+	select {
+	case w.mill <- struct{}{}:
+	default:
+		// the mill is already pending
+	}
+	return old.Close()
+}
+
+// backupName returns an unused name for a rotated file
+func (w *fileWriter) backupName() string {
+	t := w.now()
+	if !w.opts.LocalTime {
+		t = t.UTC()
+	}
+	prefix, ext := w.nameParts()
+	for {
+		name := prefix + t.Format(backupTimeFormat) + ext
+		_, err := os.Stat(name)
+		_, errGz := os.Stat(name + ".gz")
+		if os.IsNotExist(err) && os.IsNotExist(errGz) {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// nameParts returns the path of rotated files before and after the time
+func (w *fileWriter) nameParts() (prefix, ext string) {
+	ext = filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext) + "-", ext
+}
+
+// backup is a rotated file
+type backup struct {
+	path string
+	time time.Time
+}
+
+// backups returns the rotated files sorted by time, newest first
+func (w *fileWriter) backups() ([]backup, error) {
+	prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, e := range entries {
+		path := filepath.Join(filepath.Dir(w.path), e.Name())
+		ts := strings.TrimSuffix(path, ".gz")
+		if e.IsDir() || !strings.HasPrefix(ts, prefix) || !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimPrefix(ts, prefix), ext)
+		loc := time.UTC
+		if w.opts.LocalTime {
+			loc = time.Local
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, ts, loc)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: path, time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	return backups, nil
+}
+
+func (w *fileWriter) runMill() {
+	defer close(w.millDone)
+	for range w.mill {
+		// errors cannot be logged: we are the log
+		_ = w.millRun()
+	}
+}
+
+// millRun deletes and compresses rotated files according to the options
+func (w *fileWriter) millRun() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	cutoff := w.now().Add(-w.opts.MaxAge)
+	for i, b := range backups {
+		if (w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups) || (w.opts.MaxAge > 0 && b.time.Before(cutoff)) {
+			errs = append(errs, os.Remove(b.path))
+			continue
+		}
+		if w.opts.Compress && !strings.HasSuffix(b.path, ".gz") {
+			errs = append(errs, compressFile(b.path))
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compressFile gzips path to path.gz and removes path
+func compressFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(out.Name())
+		}
+	}()
+	gz := gzip.NewWriter(out)
+	if _, err = io.Copy(gz, in); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// Reopen closes the file and opens path again
+func (w *fileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	old := w.f
+	if err := w.open(); err != nil {
+		return err
+	}
+	return old.Close()
+}
+
+// closed reports if the file is closed
+func (w *fileWriter) closed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f == nil
+}
+
+// Close closes the file and waits for the background work to finish
+func (w *fileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	err := w.f.Close()
+	w.f = nil
+	close(w.mill)
+	<-w.millDone
+	return err
+}
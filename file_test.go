@@ -0,0 +1,225 @@
+package hcl
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is a clock advanced by the test
+type testClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *testClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *testClock) add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func openTestFile(t *testing.T, opts RotateOpts) (*fileWriter, *testClock, string) {
+	dir := t.TempDir()
+	clock := &testClock{t: time.Date(2022, 2, 25, 9, 40, 12, 0, time.UTC)}
+	w, err := openFile(filepath.Join(dir, "app.log"), opts)
+	assert.NoError(t, err)
+	w.now = clock.now
+	w.opened = clock.now()
+	return w, clock, dir
+}
+
+func dirFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		assert.NoError(t, err)
+		r = gz
+	}
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestFileRotateSize(t *testing.T) {
+	w, clock, dir := openTestFile(t, RotateOpts{MaxSize: 10})
+	w.Write([]byte("0123456\n"))
+	clock.add(time.Second)
+	w.Write([]byte("abcdefg\n"))
+	clock.add(time.Second)
+	// the file is never rotated when it is empty
+	w.Write([]byte("a line longer than max size\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-02-25T09-40-13.000.log",
+		"app-2022-02-25T09-40-14.000.log",
+		"app.log",
+	}, dirFiles(t, dir))
+	assert.Equal(t, "0123456\n", readFile(t, filepath.Join(dir, "app-2022-02-25T09-40-13.000.log")))
+	assert.Equal(t, "abcdefg\n", readFile(t, filepath.Join(dir, "app-2022-02-25T09-40-14.000.log")))
+	assert.Equal(t, "a line longer than max size\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestFileRotateInterval(t *testing.T) {
+	w, clock, dir := openTestFile(t, RotateOpts{Interval: time.Hour})
+	w.Write([]byte("first\n"))
+	clock.add(59 * time.Minute)
+	w.Write([]byte("first\n"))
+	clock.add(time.Minute)
+	w.Write([]byte("second\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2022-02-25T10-40-12.000.log", "app.log"}, dirFiles(t, dir))
+	assert.Equal(t, "first\nfirst\n", readFile(t, filepath.Join(dir, "app-2022-02-25T10-40-12.000.log")))
+	assert.Equal(t, "second\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestFileRotateIntervalEmpty(t *testing.T) {
+	w, clock, dir := openTestFile(t, RotateOpts{Interval: time.Hour, Compress: true})
+	clock.add(3 * time.Hour)
+	// empty files are not rotated
+	w.Write([]byte("first\n"))
+	clock.add(59 * time.Minute)
+	w.Write([]byte("first\n"))
+	clock.add(time.Minute)
+	w.Write([]byte("second\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2022-02-25T13-40-12.000.log.gz", "app.log"}, dirFiles(t, dir))
+	assert.Equal(t, "first\nfirst\n", readFile(t, filepath.Join(dir, "app-2022-02-25T13-40-12.000.log.gz")))
+	assert.Equal(t, "second\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestFileRotateFailure(t *testing.T) {
+	w, _, dir := openTestFile(t, RotateOpts{MaxSize: 10})
+	w.Write([]byte("0123456\n"))
+	// the rotation fails since the file is gone
+	assert.NoError(t, os.Remove(filepath.Join(dir, "app.log")))
+	n, err := w.Write([]byte("abcdefg\n"))
+	assert.Error(t, err)
+	assert.Equal(t, 8, n)
+
+	// the file is still usable
+	assert.NoError(t, w.Reopen())
+	_, err = w.Write([]byte("reopened\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "reopened\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestFileRotateBackups(t *testing.T) {
+	w, clock, dir := openTestFile(t, RotateOpts{MaxSize: 1, MaxBackups: 2, Compress: true})
+	for _, line := range []string{"1\n", "2\n", "3\n", "4\n"} {
+		w.Write([]byte(line))
+		clock.add(time.Millisecond)
+	}
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-02-25T09-40-12.002.log.gz",
+		"app-2022-02-25T09-40-12.003.log.gz",
+		"app.log",
+	}, dirFiles(t, dir))
+	assert.Equal(t, "2\n", readFile(t, filepath.Join(dir, "app-2022-02-25T09-40-12.002.log.gz")))
+	assert.Equal(t, "3\n", readFile(t, filepath.Join(dir, "app-2022-02-25T09-40-12.003.log.gz")))
+	assert.Equal(t, "4\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestFileRotateMaxAge(t *testing.T) {
+	w, clock, dir := openTestFile(t, RotateOpts{Interval: 24 * time.Hour, MaxAge: 48 * time.Hour})
+	for i := 0; i < 4; i++ {
+		w.Write([]byte("day\n"))
+		clock.add(24 * time.Hour)
+	}
+	w.Write([]byte("today\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-02-27T09-40-12.000.log",
+		"app-2022-02-28T09-40-12.000.log",
+		"app-2022-03-01T09-40-12.000.log",
+		"app.log",
+	}, dirFiles(t, dir))
+}
+
+func TestFileReopen(t *testing.T) {
+	w, _, dir := openTestFile(t, RotateOpts{})
+	w.Write([]byte("before\n"))
+	assert.NoError(t, os.Rename(filepath.Join(dir, "app.log"), filepath.Join(dir, "moved.log")))
+	assert.NoError(t, w.Reopen())
+	w.Write([]byte("after\n"))
+	assert.NoError(t, w.Close())
+	assert.Error(t, w.Close())
+	_, err := w.Write([]byte("closed\n"))
+	assert.Error(t, err)
+
+	assert.Equal(t, "before\n", readFile(t, filepath.Join(dir, "moved.log")))
+	assert.Equal(t, "after\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestWithFile(t *testing.T) {
+	keepDefault(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	opts := hclog.LoggerOptions{DisableTime: true}
+	l := New(WithName("file"), WithLevel(hclog.Info), WithLoggerOptions(&opts), WithStdlib(false), WithFile(path, RotateOpts{MaxSize: 1024, MaxBackups: 3}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				l.Named("worker").Info("text to output", "n", n)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, l.w.(io.Closer).Close())
+
+	files := dirFiles(t, dir)
+	assert.Len(t, files, 4)
+	lines := 0
+	for _, f := range files {
+		out := readFile(t, filepath.Join(dir, f))
+		assert.LessOrEqual(t, len(out), 1024)
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			assert.True(t, strings.HasPrefix(line, "[INFO]  file.worker: text to output: n="), line)
+			lines++
+		}
+	}
+	assert.Less(t, lines, 800)
+
+	l = New(WithName("file"), WithWriter(&buf), WithStdlib(false), WithFile(filepath.Join(dir, "missing", "app.log"), RotateOpts{}))
+	assert.Equal(t, &buf, l.w)
+	assert.Contains(t, buf.Line(), "file: cannot open log file: open ")
+}
@@ -0,0 +1,339 @@
+package hcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Format is the format of log lines
+//
+// The field names of the structured formats are stable:
+//
+//	JSON:   time, level, logger, msg
+//	logfmt: time, level, logger, msg
+//	ECS:    @timestamp, log.level, log.logger, message, ecs.version
+//
+// followed by the key/value pairs. The logger is omitted if the name is empty.
+// Keys using one of these names are prefixed by "arg_".
+// Errors are written as objects {"message": ..., "type": ...} in JSON and ECS,
+// the key err is written as error in ECS.
+type Format int
+
+const (
+	// FormatText is the hclog text format: TIME [LEVEL] name: msg: k=v
+	FormatText Format = iota
+	// FormatJSON writes a JSON object per line
+	FormatJSON
+	// FormatLogfmt writes key=value pairs
+	FormatLogfmt
+	// FormatECS writes JSON objects of the Elastic Common Schema
+	FormatECS
+)
+
+// WithFormat sets the format of the output
+func WithFormat(f Format) LoggerOpt {
+	return func(l *Logger) {
+		l.format = f
+		l.formatSet = true
+	}
+}
+
+// ParseFormat returns the format of a name: text, json, logfmt or ecs
+func ParseFormat(name string) (Format, error) {
+	for _, f := range []Format{FormatText, FormatJSON, FormatLogfmt, FormatECS} {
+		if strings.EqualFold(name, f.String()) {
+			return f, nil
+		}
+	}
+	return FormatText, fmt.Errorf("unknown format %q", name)
+}
+
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	case FormatLogfmt:
+		return "logfmt"
+	case FormatECS:
+		return "ecs"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+const (
+	// JSONTimeFormat is the time format of JSON lines
+	JSONTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	// LogfmtTimeFormat is the time format of logfmt lines
+	LogfmtTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+	// ECSTimeFormat is the time format of ECS lines (always UTC)
+	ECSTimeFormat = "2006-01-02T15:04:05.000Z"
+	// ECSVersion is the ecs.version of ECS lines
+	ECSVersion = "1.6.0"
+)
+
+// lineEncoder appends a formatted line to b
+type lineEncoder func(b []byte, t time.Time, name string, level hclog.Level, msg string, args []interface{}) []byte
+
+// encoder returns the encoder of the format, nil for the text format written by hclog
+func (f Format) encoder() lineEncoder {
+	switch f {
+	case FormatJSON:
+		return appendJSON
+	case FormatLogfmt:
+		return appendLogfmt
+	case FormatECS:
+		return appendECS
+	}
+	return nil
+}
+
+// linePool holds the buffers of the encoders
+var linePool = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+// reservedKey returns the key of an arg, prefixed if it is one of the field names
+func reservedKey(k string, reserved ...string) string {
+	for _, r := range reserved {
+		if k == r {
+			return "arg_" + k
+		}
+	}
+	return k
+}
+
+// appendLogfmt appends a logfmt line
+// the time is omitted if t is zero
+func appendLogfmt(b []byte, t time.Time, name string, level hclog.Level, msg string, args []interface{}) []byte {
+	if !t.IsZero() {
+		b = append(b, "time="...)
+		b = t.AppendFormat(b, LogfmtTimeFormat)
+		b = append(b, ' ')
+	}
+	b = append(b, "level="...)
+	b = append(b, level.String()...)
+	if name != "" {
+		b = append(b, " logger="...)
+		b = appendLogfmtValue(b, name)
+	}
+	b = append(b, " msg="...)
+	b = appendLogfmtValue(b, msg)
+	for _, p := range stringPairs(args) {
+		b = append(b, ' ')
+		b = append(b, logfmtKey(reservedKey(p[0], "time", "level", "logger", "msg"))...)
+		b = append(b, '=')
+		b = appendLogfmtValue(b, p[1])
+	}
+	return append(b, '\n')
+}
+
+// logfmtKey replaces the characters not allowed in keys by _
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+// appendLogfmtValue appends v, quoted if needed
+func appendLogfmtValue(b []byte, v string) []byte {
+	if v == "" {
+		return append(b, `""`...)
+	}
+	if strings.IndexFunc(v, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError
+	}) < 0 {
+		return append(b, v...)
+	}
+	return strconv.AppendQuote(b, v)
+}
+
+// appendJSON appends a JSON line
+// the time is omitted if t is zero
+func appendJSON(b []byte, t time.Time, name string, level hclog.Level, msg string, args []interface{}) []byte {
+	b = append(b, '{')
+	if !t.IsZero() {
+		b = append(b, `"time":"`...)
+		b = t.AppendFormat(b, JSONTimeFormat)
+		b = append(b, `",`...)
+	}
+	b = append(b, `"level":"`...)
+	b = append(b, level.String()...)
+	b = append(b, '"')
+	if name != "" {
+		b = append(b, `,"logger":`...)
+		b = appendJSONString(b, name)
+	}
+	b = append(b, `,"msg":`...)
+	b = appendJSONString(b, msg)
+	b = appendJSONArgs(b, args, func(k string, v interface{}) string {
+		return reservedKey(k, "time", "level", "logger", "msg")
+	})
+	return append(b, "}\n"...)
+}
+
+// appendECS appends an Elastic Common Schema line
+func appendECS(b []byte, t time.Time, name string, level hclog.Level, msg string, args []interface{}) []byte {
+	b = append(b, `{"@timestamp":"`...)
+	b = t.UTC().AppendFormat(b, ECSTimeFormat)
+	b = append(b, `","log.level":"`...)
+	b = append(b, level.String()...)
+	b = append(b, '"')
+	if name != "" {
+		b = append(b, `,"log.logger":`...)
+		b = appendJSONString(b, name)
+	}
+	b = append(b, `,"message":`...)
+	b = appendJSONString(b, msg)
+	b = append(b, `,"ecs.version":"`+ECSVersion+`"`...)
+	b = appendJSONArgs(b, args, func(k string, v interface{}) string {
+		if _, ok := v.(error); ok && k == "err" {
+			return "error"
+		}
+		return reservedKey(k, "@timestamp", "log.level", "log.logger", "message", "ecs.version")
+	})
+	return append(b, "}\n"...)
+}
+
+// appendJSONArgs appends the key/value pairs as members of an object
+func appendJSONArgs(b []byte, args []interface{}, key func(k string, v interface{}) string) []byte {
+	for i := 0; i < len(args); i += 2 {
+		k, ok := args[i].(string)
+		if !ok {
+			k = fmt.Sprint(args[i])
+		}
+		var v interface{}
+		if i+1 < len(args) {
+			v = args[i+1]
+		} else {
+			k, v = "EXTRA_VALUE_AT_END", args[i]
+		}
+		b = append(b, ',')
+		b = appendJSONString(b, key(k, v))
+		b = append(b, ':')
+		b = appendJSONValue(b, v)
+	}
+	return b
+}
+
+// jsonError is the object of an error
+type jsonError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// appendJSONValue appends v as JSON
+// values which cannot be marshaled are written as strings
+func appendJSONValue(b []byte, v interface{}) []byte {
+	switch val := v.(type) {
+	case string:
+		return appendJSONString(b, val)
+	case error:
+		v = jsonError{Message: val.Error(), Type: fmt.Sprintf("%T", val)}
+	case hclog.Format:
+		if len(val) < 1 {
+			return append(b, `""`...)
+		}
+		return appendJSONString(b, fmt.Sprintf(fmt.Sprint(val[0]), val[1:]...))
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fmt.Sprint(v))
+	}
+	return append(b, j...)
+}
+
+// appendJSONString appends s as JSON string
+func appendJSONString(b []byte, s string) []byte {
+	j, _ := json.Marshal(s)
+	return append(b, j...)
+}
+
+// WriterSinkOpts configures a WriterSink
+type WriterSinkOpts struct {
+	// Format of the lines (default FormatText)
+	Format Format
+	// Color of text lines
+	Color hclog.ColorOption
+	// TimeFormat of text lines (default TimeFormat)
+	TimeFormat string
+}
+
+// WriterSink is a sink writing formatted lines to a writer
+// it implements hclog.SinkAdapter
+type WriterSink struct {
+	w      io.Writer
+	format Format
+	now    func() time.Time
+
+	// mu serializes the lines
+	mu sync.Mutex
+	// backend formats text lines at the time at, noTime those without time
+	backend hclog.Logger
+	noTime  hclog.Logger
+	at      time.Time
+	buf     []byte
+}
+
+// NewWriterSink creates a sink writing to w
+// use it with WithSink or AddSink
+func NewWriterSink(w io.Writer, opts WriterSinkOpts) *WriterSink {
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = TimeFormat
+	}
+	s := &WriterSink{w: w, format: opts.Format, now: time.Now}
+	hcOpts := hclog.LoggerOptions{
+		Output:     w,
+		Level:      hclog.Trace,
+		Color:      opts.Color,
+		TimeFormat: opts.TimeFormat,
+		TimeFn:     func() time.Time { return s.at },
+	}
+	s.backend = hclog.New(&hcOpts)
+	hcOpts.DisableTime = true
+	s.noTime = hclog.New(&hcOpts)
+	return s
+}
+
+// Accept writes a line
+func (s *WriterSink) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	s.AcceptAt(s.now(), name, level, msg, args...)
+}
+
+// AcceptAt writes a line with the time t, e.g. to convert lines read from a log
+// the time is omitted if t is zero (except for FormatECS)
+func (s *WriterSink) AcceptAt(t time.Time, name string, level hclog.Level, msg string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enc := s.format.encoder()
+	if enc == nil {
+		backend := s.backend
+		if t.IsZero() {
+			backend = s.noTime
+		}
+		s.at = t
+		backend.ResetNamed(name).Log(level, msg, args...)
+		return
+	}
+	s.buf = enc(s.buf[:0], t, name, level, msg, args)
+	_, _ = writeLevel(s.w, level, s.buf)
+}
+
+// Close closes the writer (stdout and stderr are never closed)
+func (s *WriterSink) Close() error {
+	return closeWriter(s.w)
+}
@@ -0,0 +1,101 @@
+package hcl
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// withFixedTime sets the clock of the logger to a fixed time
+// it must follow WithLoggerOptions
+func withFixedTime() LoggerOpt {
+	return func(l *Logger) {
+		opts := *l.hcOpts
+		opts.TimeFn = func() time.Time { return time.Date(2022, 2, 25, 9, 40, 12, 123456000, time.UTC) }
+		l.hcOpts = &opts
+	}
+}
+
+// assertGolden compares out with testdata/name.golden
+func assertGolden(t *testing.T, name string, out []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		assert.NoError(t, os.MkdirAll("testdata", 0o755))
+		assert.NoError(t, os.WriteFile(golden, out, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(out))
+}
+
+func TestFormatGolden(t *testing.T) {
+	for _, f := range []Format{FormatText, FormatJSON, FormatLogfmt, FormatECS} {
+		t.Run(f.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Trace), WithStdlib(false), WithFormat(f), withFixedTime())
+			l.Info("started", "port", 8080, "tls", true)
+			l.Named("web").With("req", "r-1").Warn(`slow "request"`, "ms", 1500.5)
+			l.Error("failed", "err", errors.New("connection refused"), "tags", []string{"a", "b"})
+			l.Debug("collision", "msg", "arg", "level", 1, "message", "x")
+			l.Trace("format", "hex", hclog.Fmt("%x", 255), "dangling")
+			l.ResetNamed("").Infof("no name %d", 1)
+			assertGolden(t, "format_"+f.String(), out.Bytes())
+		})
+	}
+}
+
+func TestWithFormat(t *testing.T) {
+	keepDefault(t)
+	var out bytes.Buffer
+	t.Setenv(EnvFormat, "json")
+	New(WithName("env"), WithWriter(&out), WithLevel(hclog.Info), WithFormat(FormatLogfmt)).Info("text")
+	assert.Contains(t, out.String(), "level=info logger=env msg=text\n")
+
+	f, err := ParseFormat("ECS")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatECS, f)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestFormatNoTime(t *testing.T) {
+	t.Setenv(EnvTimeFormat, "none")
+	tests := []struct {
+		opt  LoggerOpt
+		want string
+	}{
+		{WithFormat(FormatJSON), `{"level":"info","logger":"app","msg":"text"}` + "\n"},
+		{WithFormat(FormatLogfmt), "level=info logger=app msg=text\n"},
+		// @timestamp is required
+		{WithFormat(FormatECS), `{"@timestamp":"2022-02-25T09:40:12.123Z","log.level":"info","log.logger":"app","message":"text","ecs.version":"1.6.0"}` + "\n"},
+		{WithLineTemplate("{time} {level}: {msg}"), "INFO: text\n"},
+	}
+	for _, tc := range tests {
+		var out bytes.Buffer
+		newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Info), WithStdlib(false), tc.opt, withFixedTime()).Info("text")
+		assert.Equal(t, tc.want, out.String())
+	}
+}
+
+func TestFormatRecorder(t *testing.T) {
+	var out bytes.Buffer
+	l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Error), WithStdlib(false),
+		WithFormat(FormatJSON), WithRecorder(RecorderOpts{Size: 4}), withFixedTime())
+	l.Debug("context", "k", 1)
+	l.Error("failed")
+	assert.Equal(t, `{"time":"2022-02-25T09:40:12.123456Z","level":"info","logger":"app","msg":"flight recorder start","lines":1}
+{"time":"2022-02-25T09:40:12.123456Z","level":"debug","logger":"app","msg":"context","k":1}
+{"time":"2022-02-25T09:40:12.123456Z","level":"info","logger":"app","msg":"flight recorder end"}
+{"time":"2022-02-25T09:40:12.123456Z","level":"error","logger":"app","msg":"failed"}
+`, out.String())
+}
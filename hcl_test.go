@@ -27,10 +27,12 @@ func (tw *testWriter) Line() string {
 }
 
 func TestDefault(t *testing.T) {
+	keepDefault(t)
 	var buf testWriter
-	initDefaultLogger()
-	actLog.SetWriter(&buf)
-	actLog.SetLevel(hclog.Trace)
+	dl := *newLogger()
+	dl.SetWriter(&buf)
+	dl.SetLevel(hclog.Trace)
+	SetDefault(dl)
 	Printf("text to output: %s %d", "string", 42)
 	assert.Equal(t, "[INFO]  go-hcl: text to output: string 42\n", buf.Line())
 	Print("text to output")
@@ -341,7 +343,7 @@ func TestLibraryLogger(t *testing.T) {
 	gologger.Println("text to output")
 	assert.Equal(t, "[INFO]  base: text to output\n", buf.Line())
 
-	actLog = nil
+	defaultLogger.Store(nil)
 	gologger.SetOutput(os.Stderr)
 	libLog = LibraryLogger("libName")
 	libLog.SetWriter(&buf)
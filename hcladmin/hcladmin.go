@@ -0,0 +1,188 @@
+// Package hcladmin provides a http.Handler to inspect and change
+// the levels of hcl loggers at runtime
+//
+// Mount it on an internal debug mux:
+//
+//	mux.Handle("/debug/log", hcladmin.New())
+//
+// GET lists all known loggers with their effective level as JSON.
+//
+// PUT or POST changes the level of a logger name and its sub-loggers
+// using the form values name, level and the optional ttl (e.g. 10m).
+// After the ttl the previous level is restored.
+//
+// DELETE removes the level configured for the query value name.
+package hcladmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vogtp/go-hcl"
+)
+
+// LoggerLevel describes the level of a logger name
+type LoggerLevel struct {
+	// Name of the logger
+	Name string `json:"name"`
+	// Level is the effective level
+	Level string `json:"level"`
+	// Configured is the level configured for the name (see hcl.SetNamedLevel)
+	Configured string `json:"configured,omitempty"`
+	// Expires is the time the configured level is reverted
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// Handler serves the level administration
+type Handler struct {
+	mu      sync.Mutex
+	reverts map[string]*revert
+}
+
+// revert restores the level of a name after a ttl
+type revert struct {
+	timer   *time.Timer
+	level   hclog.Level
+	expires time.Time
+}
+
+// New creates a level administration handler
+func New() *Handler {
+	return &Handler{reverts: make(map[string]*revert)}
+}
+
+// ServeHTTP lists or changes the levels
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, h.Levels())
+	case http.MethodPut, http.MethodPost:
+		h.setLevel(w, r)
+	case http.MethodDelete:
+		h.deleteLevel(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Levels returns the levels of all known loggers sorted by name
+// names configured without a logger are included
+func (h *Handler) Levels() []LoggerLevel {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	configured := hcl.NamedLevels()
+	levels := make([]LoggerLevel, 0, len(configured))
+	for _, info := range hcl.Loggers() {
+		levels = append(levels, h.loggerLevel(info.Name, info.Level, info.Configured))
+		delete(configured, info.Name)
+	}
+	for name, lvl := range configured {
+		levels = append(levels, h.loggerLevel(name, lvl, lvl))
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Name < levels[j].Name })
+	return levels
+}
+
+// level returns the level of name
+func (h *Handler) level(name string) LoggerLevel {
+	configured := hcl.NamedLevels()[name]
+	lvl := configured
+	if l, ok := hcl.Lookup(name); ok {
+		lvl = l.GetLevel()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.loggerLevel(name, lvl, configured)
+}
+
+// loggerLevel describes name
+// h.mu must be held
+func (h *Handler) loggerLevel(name string, lvl, configured hclog.Level) LoggerLevel {
+	ll := LoggerLevel{Name: name, Level: lvl.String()}
+	if configured != hclog.NoLevel {
+		ll.Configured = configured.String()
+	}
+	if rev, ok := h.reverts[name]; ok {
+		expires := rev.expires
+		ll.Expires = &expires
+	}
+	return ll
+}
+
+func (h *Handler) setLevel(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	lvl := hclog.LevelFromString(r.FormValue("level"))
+	if lvl == hclog.NoLevel {
+		http.Error(w, fmt.Sprintf("invalid level %q", r.FormValue("level")), http.StatusBadRequest)
+		return
+	}
+	var ttl time.Duration
+	if s := r.FormValue("ttl"); s != "" {
+		var err error
+		ttl, err = time.ParseDuration(s)
+		if err != nil || ttl <= 0 {
+			http.Error(w, fmt.Sprintf("invalid ttl %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	h.SetLevel(name, lvl, ttl)
+	writeJSON(w, http.StatusOK, h.level(name))
+}
+
+func (h *Handler) deleteLevel(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	h.SetLevel(name, hclog.NoLevel, 0)
+	writeJSON(w, http.StatusOK, h.level(name))
+}
+
+// SetLevel configures the level of name and its sub-loggers
+// if ttl is positive the previous level is restored after ttl
+// hclog.NoLevel removes the configuration of name
+func (h *Handler) SetLevel(name string, level hclog.Level, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	prev := hcl.NamedLevels()[name]
+	if rev, ok := h.reverts[name]; ok {
+		// keep the level from before the first temporary change
+		rev.timer.Stop()
+		prev = rev.level
+		delete(h.reverts, name)
+	}
+	hcl.SetNamedLevel(name, level)
+	if ttl <= 0 {
+		return
+	}
+	rev := &revert{level: prev, expires: time.Now().Add(ttl)}
+	rev.timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.reverts[name] != rev {
+			return
+		}
+		delete(h.reverts, name)
+		hcl.SetNamedLevel(name, rev.level)
+	})
+	h.reverts[name] = rev
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
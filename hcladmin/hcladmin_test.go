@@ -0,0 +1,115 @@
+package hcladmin_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+	"github.com/vogtp/go-hcl/hcladmin"
+)
+
+func do(t *testing.T, h http.Handler, method string, form url.Values) (int, []hcladmin.LoggerLevel) {
+	t.Helper()
+	var req *http.Request
+	switch method {
+	case http.MethodPut, http.MethodPost:
+		req = httptest.NewRequest(method, "/debug/log", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	default:
+		req = httptest.NewRequest(method, "/debug/log?"+form.Encode(), nil)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+	if res.StatusCode != http.StatusOK {
+		return res.StatusCode, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	var levels []hcladmin.LoggerLevel
+	if method == http.MethodGet {
+		assert.NoError(t, json.Unmarshal(body, &levels))
+		return res.StatusCode, levels
+	}
+	var level hcladmin.LoggerLevel
+	assert.NoError(t, json.Unmarshal(body, &level))
+	return res.StatusCode, []hcladmin.LoggerLevel{level}
+}
+
+func find(levels []hcladmin.LoggerLevel, name string) *hcladmin.LoggerLevel {
+	for _, l := range levels {
+		if l.Name == name {
+			return &l
+		}
+	}
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	defer hcl.SetLevels("")
+	root := hcl.New(hcl.WithName("admin"), hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Info), hcl.WithStdlib(false), hcl.WithDefault(false))
+	web := root.Named("web")
+	h := hcladmin.New()
+
+	code, levels := do(t, h, http.MethodGet, nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &hcladmin.LoggerLevel{Name: "admin", Level: "info"}, find(levels, "admin"))
+	assert.Equal(t, &hcladmin.LoggerLevel{Name: "admin.web", Level: "info"}, find(levels, "admin.web"))
+
+	code, levels = do(t, h, http.MethodPut, url.Values{"name": {"admin.web"}, "level": {"debug"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, hcladmin.LoggerLevel{Name: "admin.web", Level: "debug", Configured: "debug"}, levels[0])
+	assert.True(t, web.IsDebug())
+	assert.False(t, root.IsDebug())
+
+	code, levels = do(t, h, http.MethodPost, url.Values{"name": {"admin"}, "level": {"trace"}, "ttl": {"50ms"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "trace", levels[0].Level)
+	assert.NotNil(t, levels[0].Expires)
+	assert.True(t, root.IsTrace())
+	assert.Eventually(t, func() bool { return !root.IsDebug() }, time.Second, 10*time.Millisecond)
+	_, levels = do(t, h, http.MethodGet, nil)
+	assert.Equal(t, &hcladmin.LoggerLevel{Name: "admin", Level: "info"}, find(levels, "admin"))
+	assert.Equal(t, "debug", find(levels, "admin.web").Level)
+
+	code, levels = do(t, h, http.MethodDelete, url.Values{"name": {"admin.web"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, hcladmin.LoggerLevel{Name: "admin.web", Level: "info"}, levels[0])
+	assert.False(t, web.IsDebug())
+
+	code, _ = do(t, h, http.MethodPut, url.Values{"name": {"admin"}, "level": {"loud"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(t, h, http.MethodPut, url.Values{"level": {"info"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(t, h, http.MethodPut, url.Values{"name": {"admin"}, "level": {"info"}, "ttl": {"-1s"}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(t, h, http.MethodPatch, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestSetLevelTTL(t *testing.T) {
+	defer hcl.SetLevels("")
+	root := hcl.New(hcl.WithName("ttl"), hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Info), hcl.WithStdlib(false), hcl.WithDefault(false))
+	hcl.SetNamedLevel("ttl", hclog.Warn)
+	h := hcladmin.New()
+
+	h.SetLevel("ttl", hclog.Debug, 50*time.Millisecond)
+	h.SetLevel("ttl", hclog.Trace, 50*time.Millisecond)
+	assert.Equal(t, hclog.Trace, root.GetLevel())
+	// reverts to the level before the first temporary change
+	assert.Eventually(t, func() bool { return root.GetLevel() == hclog.Warn }, time.Second, 10*time.Millisecond)
+
+	// a permanent change cancels the revert
+	h.SetLevel("ttl", hclog.Debug, 20*time.Millisecond)
+	h.SetLevel("ttl", hclog.Error, 0)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, hclog.Error, root.GetLevel())
+}
@@ -0,0 +1,73 @@
+// Package hclmetrics provides a http.Handler serving the counters
+// of hcl loggers (see hcl.Stats) in the OpenMetrics text format
+//
+// Mount it on an internal debug mux or scrape it with Prometheus:
+//
+//	mux.Handle("/metrics/log", hclmetrics.New())
+//
+// The metrics are:
+//
+//	hcl_log_lines_total{logger="app",level="error"}  lines written per logger name and level
+//	hcl_log_dropped_total                            lines dropped by outputs and sinks
+//	hcl_log_sampled_total                            lines suppressed by sampling
+//	hcl_log_write_errors_total                       lines the output failed to write
+package hclmetrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vogtp/go-hcl"
+)
+
+// ContentType is the content type of the OpenMetrics text format
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Handler serves the counters of hcl
+type Handler struct{}
+
+// New creates a metrics handler
+func New() *Handler {
+	return &Handler{}
+}
+
+// ServeHTTP writes the counters
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var buf bytes.Buffer
+	Write(&buf, hcl.Stats())
+	w.Header().Set("Content-Type", ContentType)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// Write writes the counters in the OpenMetrics text format
+func Write(buf *bytes.Buffer, s hcl.Statistics) {
+	writeHeader(buf, "hcl_log_lines", "Lines written by logger name and level.")
+	for _, ls := range s.Lines {
+		fmt.Fprintf(buf, "hcl_log_lines_total{logger=\"%s\",level=\"%s\"} %d\n", labelValue.Replace(ls.Name), ls.Level, ls.Count)
+	}
+	writeCounter(buf, "hcl_log_dropped", "Lines dropped by outputs and sinks.", s.Dropped)
+	writeCounter(buf, "hcl_log_sampled", "Lines suppressed by sampling.", s.Sampled)
+	writeCounter(buf, "hcl_log_write_errors", "Lines the output failed to write.", s.WriteErrors)
+	buf.WriteString("# EOF\n")
+}
+
+func writeHeader(buf *bytes.Buffer, name, help string) {
+	fmt.Fprintf(buf, "# TYPE %s counter\n# HELP %s %s\n", name, name, help)
+}
+
+func writeCounter(buf *bytes.Buffer, name, help string, v uint64) {
+	writeHeader(buf, name, help)
+	fmt.Fprintf(buf, "%s_total %d\n", name, v)
+}
+
+// labelValue escapes label values
+var labelValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,71 @@
+package hclmetrics_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+	"github.com/vogtp/go-hcl/hclmetrics"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	hclmetrics.Write(&buf, hcl.Statistics{
+		Lines: []hcl.LineStats{
+			{Name: "app", Level: hclog.Info, Count: 3},
+			{Name: `a"b`, Level: hclog.Error, Count: 1},
+		},
+		Dropped:     4,
+		Sampled:     5,
+		WriteErrors: 6,
+	})
+	assert.Equal(t, `# TYPE hcl_log_lines counter
+# HELP hcl_log_lines Lines written by logger name and level.
+hcl_log_lines_total{logger="app",level="info"} 3
+hcl_log_lines_total{logger="a\"b",level="error"} 1
+# TYPE hcl_log_dropped counter
+# HELP hcl_log_dropped Lines dropped by outputs and sinks.
+hcl_log_dropped_total 4
+# TYPE hcl_log_sampled counter
+# HELP hcl_log_sampled Lines suppressed by sampling.
+hcl_log_sampled_total 5
+# TYPE hcl_log_write_errors counter
+# HELP hcl_log_write_errors Lines the output failed to write.
+hcl_log_write_errors_total 6
+# EOF
+`, buf.String())
+}
+
+func TestHandler(t *testing.T) {
+	l := hcl.New(hcl.WithName("metrics"), hcl.WithWriter(io.Discard), hcl.WithLevel(hclog.Info), hcl.WithStdlib(false))
+	// the counters are process wide
+	var before uint64
+	for _, ls := range hcl.Stats().Lines {
+		if ls.Name == "metrics" && ls.Level == hclog.Error {
+			before = ls.Count
+		}
+	}
+	l.Error("failed")
+	l.Error("failed again")
+	l.Debug("not written")
+
+	rec := httptest.NewRecorder()
+	hclmetrics.New().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/log", nil))
+	res := rec.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, hclmetrics.ContentType, res.Header.Get("Content-Type"))
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), fmt.Sprintf("hcl_log_lines_total{logger=\"metrics\",level=\"error\"} %d\n", before+2))
+	assert.Contains(t, string(body), `hcl_log_lines_total{logger="metrics",level="debug"} 0`+"\n")
+
+	rec = httptest.NewRecorder()
+	hclmetrics.New().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics/log", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,25 @@
+module github.com/vogtp/go-hcl/hclotel
+
+go 1.21
+
+require (
+	github.com/hashicorp/go-hclog v1.1.0
+	github.com/stretchr/testify v1.8.4
+	github.com/vogtp/go-hcl v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel/trace v1.21.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fatih/color v1.7.0 // indirect
+	github.com/mattn/go-colorable v0.1.4 // indirect
+	github.com/mattn/go-isatty v0.0.10 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/sethvargo/go-envconfig v0.6.0 // indirect
+	github.com/suborbital/vektor v0.6.0 // indirect
+	go.opentelemetry.io/otel v1.21.0 // indirect
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/vogtp/go-hcl => ../
@@ -0,0 +1,29 @@
+// Package hclotel adds the trace and span ids of OpenTelemetry spans to hcl log lines
+//
+// it is used by importing it for its side effect:
+//
+//	import _ "github.com/vogtp/go-hcl/hclotel"
+//
+// lines logged by hcl.InfoCtx etc. or a logger of hcl.FromContext
+// then carry the trace_id and span_id of the span in the context
+package hclotel
+
+import (
+	"context"
+
+	"github.com/vogtp/go-hcl"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	hcl.RegisterTraceExtractor(Extract)
+}
+
+// Extract returns the trace and span id of the OpenTelemetry span carried by ctx
+func Extract(ctx context.Context) (traceID, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
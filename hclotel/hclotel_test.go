@@ -0,0 +1,32 @@
+package hclotel_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+	"github.com/vogtp/go-hcl/hclotel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExtract(t *testing.T) {
+	var buf bytes.Buffer
+	l := hcl.New(hcl.WithName("otel"), hcl.WithWriter(&buf), hcl.WithLevel(hclog.Info), hcl.WithStdlib(false))
+
+	ctx := context.Background()
+	_, _, ok := hclotel.Extract(ctx)
+	assert.False(t, ok)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	ctx = trace.ContextWithSpanContext(ctx, sc)
+	// the OpenTelemetry span wins over a traceparent
+	ctx = hcl.NewTraceparentContext(ctx, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	l.InfoCtx(ctx, "span")
+	assert.Contains(t, buf.String(), "otel: span: trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7\n")
+}
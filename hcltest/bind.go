@@ -0,0 +1,121 @@
+package hcltest
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vogtp/go-hcl"
+)
+
+var (
+	// bindMu guards the bound tests and the router logger
+	bindMu sync.Mutex
+	// bound are the writers of the bound tests
+	bound = make(map[*tbWriter]bool)
+	// router is the default logger while tests are bound
+	router *hcl.Logger
+	// prev is the default logger before the first test was bound
+	prev hcl.Logger
+)
+
+// Bind returns a logger writing to t.Log of the test
+// so its lines are shown next to the test and respect -v
+// pass it to the code under test, e.g. by hcl.NewContext
+//
+// the default logger writes to the innermost bound test, e.g. the subtest of a bound test,
+// lines of parallel tests cannot be told apart: they are written to the bound test
+// running them (or to stderr if there is none)
+//
+// the previous default logger is restored when the last bound test ends
+func Bind(tb testing.TB) hcl.Logger {
+	tb.Helper()
+	bindMu.Lock()
+	defer bindMu.Unlock()
+	if router == nil {
+		prev = hcl.Default()
+		l := hcl.New(hcl.WithName(prev.Name()), hcl.WithWriter(routeWriter{}), hcl.WithStdlib(false), hcl.WithDefault(false))
+		router = &l
+		hcl.SetDefault(l)
+	}
+	tw := &tbWriter{tb: tb, name: tb.Name()}
+	bound[tw] = true
+	tb.Cleanup(func() {
+		bindMu.Lock()
+		defer bindMu.Unlock()
+		tw.close()
+		delete(bound, tw)
+		if len(bound) < 1 {
+			router = nil
+			hcl.SetDefault(prev)
+		}
+	})
+	return hcl.New(hcl.WithName(prev.Name()), hcl.WithWriter(tw), hcl.WithStdlib(false), hcl.WithDefault(false))
+}
+
+// routeWriter writes the lines of the default logger to the innermost bound test
+type routeWriter struct{}
+
+func (routeWriter) Write(p []byte) (int, error) {
+	// the lock is held while logging: the test cannot end meanwhile
+	bindMu.Lock()
+	defer bindMu.Unlock()
+	if tw := innermost(); tw != nil {
+		return tw.Write(p)
+	}
+	return os.Stderr.Write(p)
+}
+
+// innermost returns the deepest bound test which is a parent or a subtest of all bound tests
+// it is nil if there is none, e.g. for parallel tests
+// bindMu must be held
+func innermost() *tbWriter {
+	var in *tbWriter
+	for tw := range bound {
+		if in != nil && len(tw.name) <= len(in.name) {
+			continue
+		}
+		related := true
+		for other := range bound {
+			if !nestedTest(tw.name, other.name) && !nestedTest(other.name, tw.name) {
+				related = false
+				break
+			}
+		}
+		if related {
+			in = tw
+		}
+	}
+	return in
+}
+
+// nestedTest reports if the test sub is parent or one of its subtests
+func nestedTest(parent, sub string) bool {
+	return sub == parent || strings.HasPrefix(sub, parent+"/")
+}
+
+// tbWriter writes to the log of a test
+type tbWriter struct {
+	mu     sync.Mutex
+	tb     testing.TB
+	name   string
+	closed bool
+}
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		// testing panics on logs after the end of the test
+		return os.Stderr.Write(p)
+	}
+	w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+func (w *tbWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+}
@@ -0,0 +1,135 @@
+package hcltest_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+	"github.com/vogtp/go-hcl/hcltest"
+)
+
+// logTB records the lines logged to the test
+type logTB struct {
+	testing.TB
+	// name of the test, that of TB if empty
+	name     string
+	mu       sync.Mutex
+	lines    []string
+	cleanups []func()
+}
+
+func (l *logTB) Helper() {}
+
+func (l *logTB) Name() string {
+	if l.name == "" {
+		return l.TB.Name()
+	}
+	return l.name
+}
+
+func (l *logTB) Log(args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *logTB) Cleanup(f func()) {
+	l.cleanups = append(l.cleanups, f)
+}
+
+func (l *logTB) end() {
+	for i := len(l.cleanups) - 1; i >= 0; i-- {
+		l.cleanups[i]()
+	}
+}
+
+func (l *logTB) Text() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func TestBind(t *testing.T) {
+	before := hcl.Default()
+	tb := &logTB{TB: t}
+	l := hcltest.Bind(tb)
+	hcl.Info("default logger")
+	l.Info("bound logger")
+	assert.Contains(t, tb.Text(), "[INFO]  "+before.Name()+": default logger")
+	assert.Contains(t, tb.Text(), "bound logger")
+
+	tb.end()
+	assert.Equal(t, before.Name(), hcl.Default().Name())
+	assert.Equal(t, before.GetWriter(), hcl.Default().GetWriter())
+	l.Info("after end")
+	assert.NotContains(t, tb.Text(), "after end")
+}
+
+func TestBindParallel(t *testing.T) {
+	tbs := make([]*logTB, 5)
+	var wg sync.WaitGroup
+	for i := range tbs {
+		tbs[i] = &logTB{TB: t, name: fmt.Sprintf("%s/%d", t.Name(), i)}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := hcltest.Bind(tbs[i])
+			// the default logger cannot tell the tests apart
+			hcl.Infof("default of test %d", i)
+			var inner sync.WaitGroup
+			for n := 0; n < 10; n++ {
+				inner.Add(1)
+				go func() {
+					defer inner.Done()
+					l.Infof("bound of test %d", i)
+				}()
+			}
+			inner.Wait()
+		}(i)
+	}
+	wg.Wait()
+	for i, tb := range tbs {
+		out := tb.Text()
+		assert.Equal(t, 10, strings.Count(out, fmt.Sprintf("bound of test %d", i)))
+		for j := range tbs {
+			if j != i {
+				assert.NotContains(t, out, fmt.Sprintf("of test %d", j))
+			}
+		}
+		tb.end()
+	}
+}
+
+func TestBindSubtests(t *testing.T) {
+	parent := &logTB{TB: t, name: "TestParent"}
+	hcltest.Bind(parent)
+
+	// the default logger writes to the innermost test
+	sub := &logTB{TB: t, name: "TestParent/sub"}
+	hcltest.Bind(sub)
+	hcl.Info("in subtest")
+	sub.end()
+	hcl.Info("in parent")
+	assert.Contains(t, sub.Text(), "in subtest")
+	assert.NotContains(t, sub.Text(), "in parent")
+	assert.NotContains(t, parent.Text(), "in subtest")
+
+	// lines of parallel subtests go to their parent
+	a := &logTB{TB: t, name: "TestParent/a"}
+	b := &logTB{TB: t, name: "TestParent/b"}
+	la := hcltest.Bind(a)
+	hcltest.Bind(b)
+	hcl.Info("in parallel")
+	la.Info("in a")
+	assert.Contains(t, parent.Text(), "in parallel")
+	assert.Contains(t, a.Text(), "in a")
+	assert.Empty(t, b.Text())
+	b.end()
+	a.end()
+	parent.end()
+	assert.Contains(t, parent.Text(), "in parent")
+	assert.NotContains(t, parent.Text(), "in a")
+}
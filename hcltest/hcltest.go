@@ -0,0 +1,236 @@
+// Package hcltest captures the output of hcl loggers in unit tests
+//
+//	func TestHandler(t *testing.T) {
+//		log, rec := hcltest.New(t)
+//		handler(log)
+//		rec.AssertLogged(t, hclog.Error, "connection failed", "host", "db")
+//		rec.AssertNoErrors(t)
+//	}
+//
+// The captured lines are dumped to the test log if the test fails.
+package hcltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vogtp/go-hcl"
+)
+
+// Entry is a captured log line
+type Entry struct {
+	Time    time.Time
+	Level   hclog.Level
+	Name    string
+	Message string
+	// Args are the key/value pairs of the line (including those of With)
+	// values are decoded from JSON: numbers are float64, errors strings
+	Args map[string]interface{}
+}
+
+// String formats the entry like the hcl text output
+func (e Entry) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s [%s] %s: %s", e.Time.Format(hcl.TimeFormat), strings.ToUpper(e.Level.String()), e.Name, e.Message)
+	keys := make([]string, 0, len(e.Args))
+	for k := range e.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		sep := " "
+		if i == 0 {
+			sep = ": "
+		}
+		fmt.Fprintf(&sb, "%s%s=%v", sep, k, e.Args[k])
+	}
+	return sb.String()
+}
+
+// Recorder captures log lines as entries
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	// errs are lines which could not be decoded
+	errs []string
+}
+
+// New returns a logger which captures its output in the Recorder
+// the logger logs all levels, the default logger is not changed (see Bind)
+// if the test fails the captured lines are dumped to the test log
+//
+// opts are applied to the logger, they must not change its writer or format
+func New(tb testing.TB, opts ...hcl.LoggerOpt) (hcl.Logger, *Recorder) {
+	tb.Helper()
+	r := &Recorder{}
+	all := make([]hcl.LoggerOpt, 0, len(opts)+5)
+	all = append(all, hcl.WithLevel(hclog.Trace), hcl.WithStdlib(false))
+	all = append(all, opts...)
+	all = append(all,
+		hcl.WithDefault(false),
+		hcl.WithWriter(r),
+		hcl.WithLoggerOptions(&hclog.LoggerOptions{JSONFormat: true}),
+	)
+	l := hcl.New(all...)
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			r.Dump(tb)
+		}
+	})
+	return l, r
+}
+
+// Write decodes the JSON lines written by the logger
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) < 1 {
+			continue
+		}
+		e, err := decode(line)
+		if err != nil {
+			r.errs = append(r.errs, string(line))
+			continue
+		}
+		r.entries = append(r.entries, e)
+	}
+	return len(p), nil
+}
+
+// decode parses a JSON line of hclog
+func decode(line []byte) (Entry, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(line, &m); err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Args: make(map[string]interface{})}
+	for k, v := range m {
+		s, _ := v.(string)
+		switch k {
+		case "@level":
+			e.Level = hclog.LevelFromString(s)
+		case "@message":
+			e.Message = s
+		case "@module":
+			e.Name = s
+		case "@timestamp":
+			e.Time, _ = time.Parse("2006-01-02T15:04:05.000000Z07:00", s)
+		case "@caller":
+		default:
+			e.Args[k] = v
+		}
+	}
+	return e, nil
+}
+
+// Entries returns the captured entries
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Reset removes the captured entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.errs = nil
+}
+
+// Find returns the entries of level containing msg with the key/value pairs of args
+func (r *Recorder) Find(level hclog.Level, msg string, args ...interface{}) []Entry {
+	var found []Entry
+	for _, e := range r.Entries() {
+		if e.Level == level && strings.Contains(e.Message, msg) && matchArgs(e.Args, args) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// matchArgs checks if all key/value pairs of args are in the entry args
+func matchArgs(have map[string]interface{}, args []interface{}) bool {
+	for i := 0; i+1 < len(args); i += 2 {
+		k := fmt.Sprint(args[i])
+		v, ok := have[k]
+		if !ok || !equal(v, args[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal compares a decoded value with the value given to the logger
+func equal(have, want interface{}) bool {
+	if err, ok := want.(error); ok {
+		want = err.Error()
+	}
+	if b, err := json.Marshal(want); err == nil {
+		var norm interface{}
+		if json.Unmarshal(b, &norm) == nil && reflect.DeepEqual(have, norm) {
+			return true
+		}
+	}
+	return fmt.Sprint(have) == fmt.Sprint(want)
+}
+
+// AssertLogged checks if an entry of level containing msg with the key/value pairs of args was logged
+func (r *Recorder) AssertLogged(tb testing.TB, level hclog.Level, msg string, args ...interface{}) bool {
+	tb.Helper()
+	if len(r.Find(level, msg, args...)) > 0 {
+		return true
+	}
+	tb.Errorf("no %s line containing %q with %v logged", strings.ToUpper(level.String()), msg, args)
+	return false
+}
+
+// AssertNotLogged checks that no entry of level containing msg with the key/value pairs of args was logged
+func (r *Recorder) AssertNotLogged(tb testing.TB, level hclog.Level, msg string, args ...interface{}) bool {
+	tb.Helper()
+	found := r.Find(level, msg, args...)
+	if len(found) < 1 {
+		return true
+	}
+	tb.Errorf("unexpected line logged: %s", found[0])
+	return false
+}
+
+// AssertNoErrors checks that no entry of level ERROR or above was logged
+func (r *Recorder) AssertNoErrors(tb testing.TB) bool {
+	tb.Helper()
+	ok := true
+	for _, e := range r.Entries() {
+		if e.Level >= hclog.Error && e.Level != hclog.Off {
+			tb.Errorf("error logged: %s", e)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// Dump writes the captured lines to the test log
+func (r *Recorder) Dump(tb testing.TB) {
+	tb.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sb strings.Builder
+	for _, e := range r.entries {
+		sb.WriteString(e.String())
+		sb.WriteByte('\n')
+	}
+	for _, line := range r.errs {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	tb.Logf("captured log lines:\n%s", sb.String())
+}
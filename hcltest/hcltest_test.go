@@ -0,0 +1,65 @@
+package hcltest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+	"github.com/vogtp/go-hcl/hcltest"
+)
+
+// fakeTB records the failures of assertions
+type fakeTB struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	before := hcl.Default()
+	log, rec := hcltest.New(t, hcl.WithName("rec"))
+	log.Named("db").With("host", "db1").Error("connection failed", "try", 3, "err", errors.New("refused"))
+	log.Infof("started %d workers", 4)
+	log.Debug("debug line", "ok", true)
+	// the default logger is not captured
+	assert.Equal(t, before.Name(), hcl.Default().Name())
+	hcl.Error("package level")
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, hclog.Error, entries[0].Level)
+	assert.Equal(t, "rec.db", entries[0].Name)
+	assert.Equal(t, "connection failed", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"host": "db1", "try": 3.0, "err": "refused"}, entries[0].Args)
+	assert.Contains(t, entries[0].String(), "[ERROR] rec.db: connection failed: err=refused host=db1 try=3")
+
+	rec.AssertLogged(t, hclog.Error, "failed", "try", 3, "err", errors.New("refused"), "host", "db1")
+	rec.AssertLogged(t, hclog.Info, "4 workers")
+	rec.AssertLogged(t, hclog.Debug, "debug", "ok", true)
+	rec.AssertNotLogged(t, hclog.Error, "package level")
+	rec.AssertNotLogged(t, hclog.Error, "failed", "try", 4)
+
+	ft := &fakeTB{TB: t}
+	assert.False(t, rec.AssertLogged(ft, hclog.Warn, "failed"))
+	assert.False(t, rec.AssertNoErrors(ft))
+	assert.Len(t, ft.errors, 2)
+
+	rec.Reset()
+	assert.True(t, rec.AssertNoErrors(t))
+	log.Warn("after reset")
+	rec.Dump(ft)
+	assert.Contains(t, ft.logs[0], "[WARN] rec: after reset")
+}
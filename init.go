@@ -3,8 +3,9 @@ package hcl
 import (
 	"fmt"
 	"io"
-	gologger "log"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
 )
@@ -14,23 +15,28 @@ const (
 	TimeFormat = "2006/01/02 15:04:05"
 )
 
-var actLog *Logger
-
-// inits a logger with the binary name
-func initDefaultLogger() {
-	if actLog == nil {
-		l := New()
-		actLog = &l
+// New constructs a new logger
+// loglevel is Warn if build, Info if `go run` and Debug if `go test`
+// the environment (see EnvLevel etc) overrides the heuristics
+// LoggerOpts override the environment
+// std lib log and slog default are redirected (see RestoreStdlib)
+// the new logger becomes the default logger (see SetDefault, WithDefault)
+// under systemd it writes to journald if no output is given (see WithJournald)
+func New(opts ...LoggerOpt) Logger {
+	l := newLogger(opts...)
+	if l.setDefault {
+		SetDefault(*l)
 	}
+	return *l
 }
 
-// New constructs a new logger
-// loglevel is Error if build and info if `go run`
-// std lib logging is redirected
-func New(opts ...LoggerOpt) Logger {
+// newLogger constructs a new logger without touching the default logger
+func newLogger(opts ...LoggerOpt) *Logger {
 	l := &Logger{
 		name:          GetExecutableName(),
 		captureStdlib: true,
+		setDefault:    true,
+		outMu:         &sync.Mutex{},
 		hcOpts: &hclog.LoggerOptions{
 			TimeFormat: TimeFormat,
 		},
@@ -38,9 +44,30 @@ func New(opts ...LoggerOpt) Logger {
 	for _, opt := range opts {
 		opt(l)
 	}
+	for _, err := range l.applyEnv() {
+		l.initErrs = append(l.initErrs, fmt.Errorf("ignoring environment: %w", err))
+	}
+	if l.sinks == nil {
+		l.sinks = newSinkSet(nil)
+	}
+	if l.journald == nil && !l.outputSet && IsJournald() {
+		l.journald = &JournaldOpts{}
+	}
+	if l.journald != nil {
+		if err := l.setupJournald(*l.journald); err != nil {
+			l.initErrs = append(l.initErrs, err)
+		}
+		l.journald = nil
+	}
 	if l.w == nil {
 		l.w = os.Stderr
 	}
+	var aw *asyncWriter
+	if l.async != nil {
+		aw = newAsyncWriter(l.w, *l.async)
+		l.w = aw
+		l.async = nil
+	}
 	if l.level == hclog.NoLevel {
 		l.level = hclog.Warn
 		if IsGoRun() {
@@ -50,23 +77,40 @@ func New(opts ...LoggerOpt) Logger {
 			l.level = hclog.Debug
 		}
 	}
+	l.base = &atomic.Int32{}
+	l.base.Store(int32(l.level))
 	// this creates the backend logger
 	l.SetWriter(l.w)
-	if l.captureStdlib {
-		// sets the std lib logger to write to us
-		gologger.SetOutput(l.GetWriter())
-		gologger.SetPrefix("")
-		gologger.SetFlags(0)
+	register(l)
+	if aw != nil {
+		out := l.copy()
+		out.setOutput(aw.w)
+		// the report must not keep the name registered
+		out.entry = nil
+		report := func(dropped uint64) {
+			// bypass the level: drops have to be visible in any case
+			out.emit(hclog.Warn, "dropped log lines", []interface{}{"count", dropped})
+		}
+		aw.report.Store(&report)
 	}
-	actLog = l
-	return *l
+	for _, err := range l.initErrs {
+		l.Warnf("%v", err)
+	}
+	l.initErrs = nil
+	if l.signals != nil {
+		l.signals.start(*l)
+	}
+	return l
 }
 
 // With sreates a sublogger
 // that will always have the given key/value pairs
 func (l Logger) With(args ...interface{}) Logger {
 	sl := l.copy()
-	sl.Logger = l.Logger.With(args...)
+	if l.redactor != nil {
+		args = l.redactor.args(args)
+	}
+	sl.setBackend(l.backend().With(args...))
 	return sl
 }
 
@@ -79,7 +123,9 @@ func (l Logger) Named(name string) Logger {
 func (l Logger) ResetNamed(name string) Logger {
 	sl := l.copy()
 	sl.name = name
-	sl.Logger = l.Logger.ResetNamed(name)
+	sl.setBackend(l.backend().ResetNamed(name))
+	sl.sinks = newSinkSet(l.sinks)
+	register(&sl)
 	return sl
 }
 
@@ -87,30 +133,51 @@ func (l Logger) ResetNamed(name string) Logger {
 // if the library used hcl it creates a sublogger
 // otherwise it mimics stdlib
 func LibraryLogger(name string) Logger {
-	if actLog != nil {
-		l := actLog.Named(name)
-		return l
+	if l := defaultLogger.Load(); l != nil {
+		return l.Named(name)
 	}
-	opts := hclog.LoggerOptions{TimeFormat: TimeFormat}
-	l := New(
+	// do not set the default logger (we are called from a lib)
+	l := newLogger(
 		WithName(name),
 		WithLevel(hclog.Info),
-		WithLoggerOptions(&opts),
 		WithStdlib(false),
 	)
-	// keep actLog clean (we are called from a lib)
-	actLog = nil
-	return l
+	return *l
 }
 
 // SetWriter sets the write of this logger
 // redirects the std lib log
+// the options are copied: loggers sharing them are not changed
 func (l *Logger) SetWriter(w io.Writer) {
+	l.setOutput(w)
+	if l.entry != nil {
+		l.entry.setWriter(w)
+	}
+}
+
+// setOutput creates the backend writing to w
+func (l *Logger) setOutput(w io.Writer) {
+	opts := *l.hcOpts
+	opts.Name = l.name
+	opts.Output = w
+	// levels are checked by hcl: the backend writes everything (see hclogAdapter)
+	opts.Level = hclog.Trace
 	l.w = w
-	l.hcOpts.Name = l.name
-	l.hcOpts.Output = w
-	l.hcOpts.Level = l.level
-	l.Logger = hclog.New(l.hcOpts)
+	l.hcOpts = &opts
+	// hclog gets a copy writing through statsWriter
+	backend := opts
+	backend.Output = statsWriter{w: w}
+	if backend.Color == hclog.AutoColor {
+		// hclog detects terminals only on *os.File outputs
+		backend.Color = hclog.ColorOff
+		if isTerminal(w) {
+			backend.Color = hclog.ForceColor
+		}
+	}
+	l.setBackend(hclog.New(&backend))
+	if l.template != nil {
+		l.template = l.template.forOutput(w, l.hcOpts)
+	}
 }
 
 // GetWriter returns a writer
@@ -133,6 +200,7 @@ func WithName(name string) LoggerOpt {
 func WithWriter(w io.Writer) LoggerOpt {
 	return func(l *Logger) {
 		l.w = w
+		l.outputSet = true
 	}
 }
 
@@ -145,15 +213,25 @@ func WithLevel(lvl hclog.Level) LoggerOpt {
 
 // WithLoggerOptions sets the logger options of hclog
 // Name, Level, Output get overwritter by hcl options
+// the environment does not change the format of loggers with options
 func WithLoggerOptions(opts *hclog.LoggerOptions) LoggerOpt {
 	return func(l *Logger) {
 		l.hcOpts = opts
+		l.customOpts = true
 	}
 }
 
-// WithStdlib controls if stdlib logger should be changed
+// WithStdlib controls if stdlib logger and slog default should be changed
 func WithStdlib(b bool) LoggerOpt {
 	return func(l *Logger) {
 		l.captureStdlib = b
 	}
 }
+
+// WithDefault controls if New makes the logger the default logger
+// without it neither the default logger nor stdlib log and slog are changed
+func WithDefault(b bool) LoggerOpt {
+	return func(l *Logger) {
+		l.setDefault = b
+	}
+}
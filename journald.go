@@ -0,0 +1,179 @@
+package hcl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// JournaldSocket is the socket of the journald native protocol
+const JournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldOpts configures the journald output of WithJournald
+type JournaldOpts struct {
+	// Socket is the journald socket (default JournaldSocket)
+	Socket string
+	// Identifier is the SYSLOG_IDENTIFIER of all entries
+	// the logger name is used if empty
+	Identifier string
+}
+
+// WithJournald is used to create a logger writing to journald
+// the lines are sent as journal entries with the key/value pairs as fields
+// and are not written to the output, unless it is set by WithWriter
+//
+// New uses journald if no output is given (by WithWriter, WithFile, WithSyslog or HCL_OUTPUT)
+// and stderr is connected to the journal (see IsJournald)
+func WithJournald(opts JournaldOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.journald = &opts
+	}
+}
+
+// Journald sends log lines as journal entries using the native protocol
+// it implements hclog.SinkAdapter
+//
+// The entries are sent to the socket path, so a restart of journald does not break them.
+// Entries which cannot be sent are counted as dropped (see Stats).
+type Journald struct {
+	opts JournaldOpts
+	addr *net.UnixAddr
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// NewJournald opens a socket sending to journald
+// it fails if the journald socket does not exist
+func NewJournald(opts JournaldOpts) (*Journald, error) {
+	if opts.Socket == "" {
+		opts.Socket = JournaldSocket
+	}
+	if _, err := os.Stat(opts.Socket); err != nil {
+		return nil, err
+	}
+	// the socket is not connected: a connection would break when journald restarts
+	c, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &Journald{opts: opts, addr: &net.UnixAddr{Name: opts.Socket, Net: "unixgram"}, conn: c}, nil
+}
+
+// Accept sends a log line as journal entry
+// PRIORITY is the syslog severity (see SyslogSeverity), SYSLOG_IDENTIFIER the logger name
+// and the keys of the key/value pairs are converted to field names (e.g. "request-id" to REQUEST_ID)
+func (j *Journald) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	var buf bytes.Buffer
+	journalField(&buf, "MESSAGE", msg)
+	journalField(&buf, "PRIORITY", strconv.Itoa(SyslogSeverity(level)))
+	id := j.opts.Identifier
+	if id == "" {
+		id = name
+	} else if name != "" {
+		journalField(&buf, "LOGGER", name)
+	}
+	if id != "" {
+		journalField(&buf, "SYSLOG_IDENTIFIER", id)
+	}
+	for _, p := range stringPairs(args) {
+		journalField(&buf, journalName(p[0]), p[1])
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.conn == nil {
+		return
+	}
+	if err := j.send(buf.Bytes()); err != nil {
+		droppedLines.Add(1)
+	}
+}
+
+// send sends an entry as datagram
+// entries too large for a datagram are passed in a sealed memfd
+func (j *Journald) send(b []byte) error {
+	_, _, err := j.conn.WriteMsgUnix(b, nil, j.addr)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		return sendJournalFile(j.conn, j.addr, b)
+	}
+	return err
+}
+
+// Close closes the connection
+func (j *Journald) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.conn == nil {
+		return nil
+	}
+	err := j.conn.Close()
+	j.conn = nil
+	return err
+}
+
+// journalField appends a field in the native protocol
+// values with newlines are length prefixed
+func journalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalName converts a key to a journal field name:
+// upper case letters, digits and underscores, not starting with an underscore or digit
+// names of the journal fields set by hcl are prefixed by "ARG_"
+func journalName(key string) string {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key) && len(b) < 64; i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		case 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		default:
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	name := strings.TrimLeft(string(b), "_")
+	switch {
+	case name == "":
+		return "ARG"
+	case name[0] >= '0' && name[0] <= '9',
+		name == "MESSAGE", name == "PRIORITY", name == "SYSLOG_IDENTIFIER", name == "LOGGER":
+		return "ARG_" + name
+	}
+	return name
+}
+
+// setupJournald adds the journald sink to l
+// the output is discarded unless set explicitly
+func (l *Logger) setupJournald(opts JournaldOpts) error {
+	j, err := NewJournald(opts)
+	if err != nil {
+		return fmt.Errorf("cannot connect journald: %w", err)
+	}
+	l.sinks.add(j, SinkOpts{})
+	if l.w == nil {
+		l.w = io.Discard
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package hcl
+
+import (
+	"net"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sendJournalFile passes an entry to journald in a sealed memfd
+// this is how journald takes entries larger than a datagram
+func sendJournalFile(conn *net.UnixConn, addr *net.UnixAddr, b []byte) error {
+	fd, err := unix.MemfdCreate("hcl-journal", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), "hcl-journal")
+	defer f.Close()
+	if _, err := f.Write(b); err != nil {
+		return err
+	}
+	// journald only reads sealed files
+	if _, err := unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, unix.F_SEAL_SHRINK|unix.F_SEAL_GROW|unix.F_SEAL_WRITE|unix.F_SEAL_SEAL); err != nil {
+		return err
+	}
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), addr)
+	return err
+}
@@ -0,0 +1,52 @@
+//go:build linux
+
+package hcl
+
+import (
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournaldLarge(t *testing.T) {
+	pc, path := listenJournal(t)
+	j, err := NewJournald(JournaldOpts{Socket: path})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer j.Close()
+	msg := strings.Repeat("x", 4<<20)
+	j.Accept("app", hclog.Info, msg)
+
+	// the entry is passed as file descriptor
+	oob := make([]byte, syscall.CmsgSpace(4))
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := pc.(*net.UnixConn).ReadMsgUnix(nil, oob)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, n)
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if !assert.NoError(t, err) || !assert.Len(t, msgs, 1) {
+		return
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if !assert.NoError(t, err) || !assert.Len(t, fds, 1) {
+		return
+	}
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+	// the file offset is shared with the sender
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<30))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "MESSAGE="+msg+"\n"))
+	_, err = f.Write([]byte("x"))
+	assert.Error(t, err, "the file is sealed")
+}
@@ -0,0 +1,158 @@
+package hcl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// listenJournal listens on a unixgram socket like journald
+func listenJournal(t *testing.T) (net.PacketConn, string) {
+	path := filepath.Join(t.TempDir(), "journal")
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	return pc, path
+}
+
+// readJournal reads an entry of the native protocol
+func readJournal(t *testing.T, pc net.PacketConn) map[string]string {
+	buf := make([]byte, 4096)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]string)
+	b := buf[:n]
+	for len(b) > 0 {
+		i := bytes.IndexAny(b, "=\n")
+		name := string(b[:i])
+		if b[i] == '=' {
+			end := bytes.IndexByte(b, '\n')
+			fields[name] = string(b[i+1 : end])
+			b = b[end+1:]
+			continue
+		}
+		size := binary.LittleEndian.Uint64(b[i+1 : i+9])
+		fields[name] = string(b[i+9 : i+9+int(size)])
+		b = b[i+9+int(size)+1:]
+	}
+	return fields
+}
+
+func TestJournald(t *testing.T) {
+	pc, path := listenJournal(t)
+	keepDefault(t)
+	l := newLogger(WithName("app"), WithLevel(hclog.Info), WithStdlib(false), WithJournald(JournaldOpts{Socket: path}))
+	defer l.Close()
+	assert.Equal(t, io.Discard, l.w)
+
+	l.Named("web").With("request-id", 7).Warn("multi\nline", "message", "arg", "2xx", 3, "err", fmt.Errorf("line1\nline2"))
+	assert.Equal(t, map[string]string{
+		"MESSAGE":           "multi\nline",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app.web",
+		"REQUEST_ID":        "7",
+		"ARG_MESSAGE":       "arg",
+		"ARG_2XX":           "3",
+		"ERR":               "line1\nline2",
+	}, readJournal(t, pc))
+}
+
+func TestJournaldEnv(t *testing.T) {
+	pc, path := listenJournal(t)
+	keepDefault(t)
+	var buf bytes.Buffer
+	t.Setenv(EnvOutput, "journald")
+	l := newLogger(WithName("env"), WithLevel(hclog.Info), WithStdlib(false), WithJournald(JournaldOpts{Socket: path, Identifier: "ident"}))
+	defer l.Close()
+	l.Error("to journal")
+	fields := readJournal(t, pc)
+	assert.Equal(t, "ident", fields["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, "env", fields["LOGGER"])
+	assert.Equal(t, "3", fields["PRIORITY"])
+
+	// a writer set explicitly is kept
+	l = newLogger(WithWriter(&buf), WithLevel(hclog.Info), WithStdlib(false), WithJournald(JournaldOpts{Socket: path}))
+	defer l.Close()
+	l.Info("both")
+	assert.Equal(t, "both", readJournal(t, pc)["MESSAGE"])
+	assert.Contains(t, buf.String(), "both")
+
+	l = newLogger(WithWriter(&buf), WithStdlib(false), WithJournald(JournaldOpts{Socket: path + "-missing"}))
+	assert.Contains(t, buf.String(), "cannot connect journald")
+}
+
+func TestJournaldRestart(t *testing.T) {
+	pc, path := listenJournal(t)
+	j, err := NewJournald(JournaldOpts{Socket: path})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer j.Close()
+	j.Accept("app", hclog.Info, "before")
+	assert.Equal(t, "before", readJournal(t, pc)["MESSAGE"])
+
+	// journald is stopped
+	pc.Close()
+	os.Remove(path)
+	dropped := droppedLines.Load()
+	j.Accept("app", hclog.Info, "lost")
+	assert.Equal(t, dropped+1, droppedLines.Load())
+
+	// and started again
+	pc, err = net.ListenPacket("unixgram", path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer pc.Close()
+	j.Accept("app", hclog.Info, "after")
+	assert.Equal(t, "after", readJournal(t, pc)["MESSAGE"])
+}
+
+func TestJournaldDetect(t *testing.T) {
+	dev, ino, ok := stderrID()
+	if !ok {
+		t.Skip("no device and inode of stderr")
+	}
+	keepDefault(t)
+	t.Setenv("JOURNAL_STREAM", fmt.Sprintf("%d:%d", dev, ino))
+	t.Setenv(EnvOutput, "")
+	path := filepath.Join(t.TempDir(), "app.log")
+	l := newLogger(WithFile(path, RotateOpts{}), WithLevel(hclog.Info), WithStdlib(false))
+	defer l.Close()
+	l.Info("to file")
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "to file")
+	assert.NotContains(t, string(b), "journald")
+
+	// stderr is kept if the file cannot be opened
+	l = newLogger(WithFile(filepath.Join(path, "missing", "app.log"), RotateOpts{}), WithStdlib(false))
+	assert.Equal(t, os.Stderr, l.w)
+}
+
+func TestIsJournald(t *testing.T) {
+	t.Setenv("JOURNAL_STREAM", "")
+	assert.False(t, IsJournald())
+	dev, ino, ok := stderrID()
+	if !ok {
+		t.Skip("no device and inode of stderr")
+	}
+	t.Setenv("JOURNAL_STREAM", fmt.Sprintf("%d:%d", dev, ino+1))
+	assert.False(t, IsJournald())
+	t.Setenv("JOURNAL_STREAM", fmt.Sprintf("%d:%d", dev, ino))
+	assert.True(t, IsJournald())
+}
@@ -0,0 +1,101 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// levelTree maps logger names to their configured level
+// guarded by registryMu
+var levelTree = map[string]hclog.Level{}
+
+// lookupLevel returns the level of the most specific name in the tree
+// matching name or one of its dotted prefixes
+// registryMu must be held
+func lookupLevel(name string) hclog.Level {
+	for {
+		if lvl, ok := levelTree[name]; ok {
+			return lvl
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return hclog.NoLevel
+		}
+		name = name[:i]
+	}
+}
+
+// updateLevels applies the level tree to all registered loggers
+// registryMu must be held
+func updateLevels() {
+	for name, e := range registry {
+		e.level.Store(int32(lookupLevel(name)))
+	}
+}
+
+// ParseLevels parses a level spec like "myapp=warn,myapp.web=debug"
+func ParseLevels(spec string) (map[string]hclog.Level, error) {
+	levels := make(map[string]hclog.Level)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, lvlStr, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid level entry %q: expected name=level", entry)
+		}
+		lvl := hclog.LevelFromString(strings.TrimSpace(lvlStr))
+		if lvl == hclog.NoLevel {
+			return nil, fmt.Errorf("invalid level %q for %s", lvlStr, name)
+		}
+		levels[name] = lvl
+	}
+	return levels, nil
+}
+
+// SetLevels replaces the per name level configuration by spec
+// e.g. "myapp=warn,myapp.web=debug,myapp.db.pool=trace"
+//
+// A logger uses the level of the most specific name matching its name
+// or one of its dotted prefixes. The configuration applies to existing
+// and future loggers and takes precedence over SetLevel.
+// An empty spec clears the configuration.
+func SetLevels(spec string) error {
+	levels, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	levelTree = levels
+	updateLevels()
+	return nil
+}
+
+// SetNamedLevel configures the level of name and its sub-loggers
+// hclog.NoLevel removes the configuration of name
+func SetNamedLevel(name string, level hclog.Level) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if level == hclog.NoLevel {
+		delete(levelTree, name)
+	} else {
+		levelTree[name] = level
+	}
+	updateLevels()
+}
+
+// NamedLevels returns a copy of the per name level configuration
+func NamedLevels() map[string]hclog.Level {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	levels := make(map[string]hclog.Level, len(levelTree))
+	for name, lvl := range levelTree {
+		levels[name] = lvl
+	}
+	return levels
+}
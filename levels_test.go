@@ -0,0 +1,76 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels(" myapp=warn, myapp.web=DEBUG,,myapp.db.pool = trace ")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]hclog.Level{
+		"myapp":         hclog.Warn,
+		"myapp.web":     hclog.Debug,
+		"myapp.db.pool": hclog.Trace,
+	}, levels)
+
+	levels, err = ParseLevels("")
+	assert.NoError(t, err)
+	assert.Empty(t, levels)
+
+	for _, spec := range []string{"myapp", "=warn", "myapp=loud"} {
+		_, err = ParseLevels(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	defer SetLevels("")
+	root := New(WithName("myapp"), WithWriter(&buf), WithLevel(hclog.Info))
+	web := root.Named("web")
+	db := root.Named("db")
+	pool := db.Named("pool")
+	webby := root.Named("webby")
+
+	assert.NoError(t, SetLevels("myapp=warn,myapp.web=debug,myapp.db.pool=trace"))
+	assert.Equal(t, hclog.Warn, root.GetLevel())
+	assert.Equal(t, hclog.Debug, web.GetLevel())
+	assert.Equal(t, hclog.Warn, db.GetLevel())
+	assert.Equal(t, hclog.Trace, pool.GetLevel())
+	assert.Equal(t, hclog.Warn, webby.GetLevel(), "prefixes match on dots only")
+	// loggers created later
+	assert.Equal(t, hclog.Debug, web.Named("handler").GetLevel())
+	assert.Equal(t, hclog.Trace, pool.With("conn", 1).GetLevel())
+	assert.True(t, IsWarn() && !IsInfo(), "default logger")
+
+	web.Debug("text to output")
+	assert.Equal(t, "[DEBUG] myapp.web: text to output\n", buf.Line())
+	db.Debugf("text to output")
+	assert.Equal(t, 0, buf.Len())
+	GetWriter().Write([]byte("[INFO] text to output"))
+	assert.Equal(t, 0, buf.Len())
+
+	// SetLevel does not override configured names
+	root.SetLevel(hclog.Error)
+	assert.Equal(t, hclog.Warn, root.GetLevel())
+
+	SetNamedLevel("myapp.db", hclog.Info)
+	assert.Equal(t, hclog.Info, db.GetLevel())
+	assert.Equal(t, hclog.Trace, pool.GetLevel())
+	SetNamedLevel("myapp.db.pool", hclog.NoLevel)
+	assert.Equal(t, hclog.Info, pool.GetLevel())
+	assert.Equal(t, map[string]hclog.Level{
+		"myapp":     hclog.Warn,
+		"myapp.web": hclog.Debug,
+		"myapp.db":  hclog.Info,
+	}, NamedLevels())
+
+	// without configuration SetLevel is used
+	assert.NoError(t, SetLevels(""))
+	assert.Equal(t, hclog.Error, root.GetLevel())
+	assert.Equal(t, hclog.Error, pool.GetLevel())
+
+	assert.Error(t, SetLevels("myapp"))
+}
@@ -1,81 +1,396 @@
 package hcl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	gologger "log"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 )
 
 // Logger implements hclog.Logger
 type Logger struct {
+	// Logger passes its calls through hcl: level, redaction and sinks apply (see hclogAdapter)
 	hclog.Logger
 
 	w      io.Writer
 	hcOpts *hclog.LoggerOptions
+	// format of the output, text is written by hclog (see WithFormat)
+	format Format
+	// formatSet is set if the format is given by WithFormat
+	formatSet bool
+	// template is the layout of text lines (see WithLineTemplate)
+	template *lineTemplate
+	// outMu serializes the lines of the other formats
+	outMu *sync.Mutex
+	// customOpts is set if hcOpts are given by WithLoggerOptions
+	customOpts bool
+	// outputSet is set if an output is given (see WithJournald)
+	outputSet bool
+
+	// level is the level the logger was created with
+	level hclog.Level
+	// base is the level shared with all related loggers (see SetLevel)
+	base *atomic.Int32
+	// entry is the registry entry of name
+	// it caches the level configured for name (see GetLevel)
+	entry *loggerRef
 
-	level         hclog.Level
 	name          string
 	captureStdlib bool
+	// setDefault makes the logger the default logger at creation (see WithDefault)
+	setDefault bool
+	// signals is started at creation (see WithSignalControl)
+	signals *SignalControl
+	// redactor replaces sensitive values (see WithRedaction)
+	redactor *redactor
+	// recorder keeps the last lines of all levels (see WithRecorder)
+	recorder *flightRecorder
+	// sinks receive the written lines in addition to w (see WithSyslog)
+	sinks *sinkSet
+	// sampler suppresses repetitive messages (see WithSampling)
+	sampler *sampler
+	// journald is set up at creation (see WithJournald)
+	journald *JournaldOpts
+	// async is set up at creation (see WithAsync)
+	async *AsyncOpts
+	// initErrs are the problems of the LoggerOpts logged at creation
+	initErrs []error
 }
 
-//creates a copy of itslef
+// creates a copy of itslef
 func (l Logger) copy() Logger {
 	n := Logger{
-		Logger: l.Logger,
-		w:      l.w,
-		hcOpts: l.hcOpts,
-		level:  l.level,
-		name:   l.name,
+		Logger:   l.Logger,
+		w:        l.w,
+		hcOpts:   l.hcOpts,
+		format:   l.format,
+		outMu:    l.outMu,
+		template: l.template,
+		level:    l.level,
+		base:     l.base,
+		entry:    l.entry,
+		name:     l.name,
+
+		sampler:  l.sampler,
+		redactor: l.redactor,
+		recorder: l.recorder,
+		sinks:    l.sinks,
+
+		customOpts:    l.customOpts,
+		captureStdlib: l.captureStdlib,
 	}
 	return n
 }
 
 // Errorf provides printf like logging to Error
 func (l Logger) Errorf(format string, v ...interface{}) {
-	l.Logger.Error(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Error) {
+		l.logf(hclog.Error, format, v)
+	}
 }
 
 // Warnf provides printf like logging to Warn
 func (l Logger) Warnf(format string, v ...interface{}) {
-	l.Logger.Warn(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Warn) {
+		l.logf(hclog.Warn, format, v)
+	}
 }
 
 // Infof provides printf like logging to Info
 func (l Logger) Infof(format string, v ...interface{}) {
-	l.Logger.Info(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Info) {
+		l.logf(hclog.Info, format, v)
+	}
 }
 
 // Debugf provides printf like logging to Debug
+// if Debug is disabled v is not formatted, but the caller still boxes
+// non-constant arguments: guard hot paths with IsDebug
 func (l Logger) Debugf(format string, v ...interface{}) {
-	l.Logger.Debug(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Debug) {
+		l.logf(hclog.Debug, format, v)
+	}
 }
 
 // Tracef provides printf like logging to Trace
+// if Trace is disabled v is not formatted, but the caller still boxes
+// non-constant arguments: guard hot paths with IsTrace
 func (l Logger) Tracef(format string, v ...interface{}) {
-	l.Logger.Trace(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Trace) {
+		l.logf(hclog.Trace, format, v)
+	}
 }
 
 // Printf works like Printf from stdlib
 // logs to Info
 func (l Logger) Printf(format string, v ...interface{}) {
-	l.Logger.Info(fmt.Sprintf(format, v...))
+	if l.enabled(hclog.Info) {
+		l.logf(hclog.Info, format, v)
+	}
 }
 
 // Print works like Print from stdlib
 // logs to Info
 func (l Logger) Print(v ...interface{}) {
-	l.Logger.Info(fmt.Sprint(v...))
+	if l.enabled(hclog.Info) {
+		l.logPrint(hclog.Info, v)
+	}
 }
 
 // Println works like hcl.Print
 // logs to Info
 func (l Logger) Println(v ...interface{}) {
-	l.Logger.Info(fmt.Sprint(v...))
+	if l.enabled(hclog.Info) {
+		l.logPrint(hclog.Info, v)
+	}
+}
+
+// Log emits a message and key/value pairs at a provided log level
+func (l Logger) Log(level hclog.Level, msg string, args ...interface{}) {
+	if l.enabled(level) {
+		l.log(level, msg, args)
+	}
+}
+
+// Trace logs a message and key/value pairs at the TRACE level
+// if Trace is disabled nothing is formatted, but the caller still allocates
+// args holding non-constant values: guard hot paths with IsTrace
+func (l Logger) Trace(msg string, args ...interface{}) {
+	if l.enabled(hclog.Trace) {
+		l.log(hclog.Trace, msg, args)
+	}
+}
+
+// Debug logs a message and key/value pairs at the DEBUG level
+// if Debug is disabled nothing is formatted, but the caller still allocates
+// args holding non-constant values: guard hot paths with IsDebug
+func (l Logger) Debug(msg string, args ...interface{}) {
+	if l.enabled(hclog.Debug) {
+		l.log(hclog.Debug, msg, args)
+	}
+}
+
+// Info logs a message and key/value pairs at the INFO level
+func (l Logger) Info(msg string, args ...interface{}) {
+	if l.enabled(hclog.Info) {
+		l.log(hclog.Info, msg, args)
+	}
+}
+
+// Warn logs a message and key/value pairs at the WARN level
+func (l Logger) Warn(msg string, args ...interface{}) {
+	if l.enabled(hclog.Warn) {
+		l.log(hclog.Warn, msg, args)
+	}
+}
+
+// Error logs a message and key/value pairs at the ERROR level
+func (l Logger) Error(msg string, args ...interface{}) {
+	if l.enabled(hclog.Error) {
+		l.log(hclog.Error, msg, args)
+	}
+}
+
+// IsTrace indicates if Trace logs would be written
+func (l Logger) IsTrace() bool {
+	return l.writes(hclog.Trace)
+}
+
+// IsDebug indicates if Debug logs would be written
+func (l Logger) IsDebug() bool {
+	return l.writes(hclog.Debug)
+}
+
+// IsInfo indicates if Info logs would be written
+func (l Logger) IsInfo() bool {
+	return l.writes(hclog.Info)
+}
+
+// IsWarn indicates if Warn logs would be written
+func (l Logger) IsWarn() bool {
+	return l.writes(hclog.Warn)
+}
+
+// IsError indicates if Error logs would be written
+func (l Logger) IsError() bool {
+	return l.writes(hclog.Error)
+}
+
+// GetLevel returns the effective level of the logger
+// a level configured for its name by SetLevels wins over SetLevel
+func (l Logger) GetLevel() hclog.Level {
+	return hclog.Level(l.effectiveLevel())
+}
+
+// effectiveLevel returns the level configured for the name or the base level
+func (l Logger) effectiveLevel() int32 {
+	if l.entry != nil {
+		if lvl := l.entry.level.Load(); lvl != int32(hclog.NoLevel) {
+			return lvl
+		}
+	}
+	return l.base.Load()
+}
+
+// enabled checks if a message of level would be written, sent to a sink or recorded
+// it is the first thing done by all log functions:
+// disabled levels must be as cheap as possible
+func (l Logger) enabled(level hclog.Level) bool {
+	return int32(level) >= l.effectiveLevel() || l.sinks.wants(level) ||
+		l.recorder != nil && l.recorder.records(level)
+}
+
+// writes checks if a message of level would be written to the output
+func (l Logger) writes(level hclog.Level) bool {
+	return int32(level) >= l.effectiveLevel()
+}
+
+// route decides where a message goes:
+// out is set if it is written to the output, sink if a leveled sink takes it
+func (l Logger) route(level hclog.Level, template string) (out, sink bool) {
+	out = l.writes(level)
+	sink = l.sinks.wants(level)
+	if (out || sink) && !l.sampled(level, template) {
+		return false, false
+	}
+	return out, sink
+}
+
+// logf formats and logs a printf like message
+// it is not inlined to keep the printf helpers small
+func (l Logger) logf(level hclog.Level, format string, v []interface{}) {
+	out, sink := l.route(level, format)
+	if !out && !sink && l.recorder == nil {
+		return
+	}
+	if l.redactor != nil {
+		v = l.redactor.printf(v)
+	}
+	l.write(level, fmt.Sprintf(format, v...), nil, out, sink)
+}
+
+// logPrint formats and logs a print like message
+func (l Logger) logPrint(level hclog.Level, v []interface{}) {
+	if l.redactor != nil {
+		v = l.redactor.printf(v)
+	}
+	l.log(level, fmt.Sprint(v...), nil)
+}
+
+// log logs a message of an enabled level
+func (l Logger) log(level hclog.Level, msg string, args []interface{}) {
+	out, sink := l.route(level, msg)
+	if !out && !sink && l.recorder == nil {
+		return
+	}
+	if l.redactor != nil {
+		args = l.redactor.args(args)
+	}
+	l.write(level, msg, args, out, sink)
+}
+
+// write records the message, writes it to the backend if out is set
+// and sends it to the sinks
+func (l Logger) write(level hclog.Level, msg string, args []interface{}, out, sink bool) {
+	if l.recorder != nil {
+		l.recorder.record(l, level, msg, args, out)
+	}
+	if out {
+		if l.recorder != nil && l.recorder.dumpsAt(level) {
+			// the context is written before the line
+			l.recorder.dumpHidden(l)
+		}
+		l.emit(level, msg, args)
+		if l.entry != nil {
+			l.entry.lines.add(level)
+		}
+	}
+	if out || sink {
+		l.sinks.accept(l, level, msg, args, out)
+	}
+}
+
+// emit writes a line to the output in the format of the logger
+// it bypasses level, sampling and sinks
+func (l Logger) emit(level hclog.Level, msg string, args []interface{}) {
+	enc := l.encoder()
+	if enc == nil {
+		l.backend().Log(level, msg, args...)
+		return
+	}
+	args = withImplied(l, args)
+	buf := linePool.Get().(*[]byte)
+	*buf = enc((*buf)[:0], l.lineTime(l.now()), l.name, level, msg, args)
+	l.outMu.Lock()
+	if _, err := writeLevel(l.w, level, *buf); err != nil {
+		writeErrors.Add(1)
+	}
+	l.outMu.Unlock()
+	linePool.Put(buf)
+}
+
+// now returns the time of a line by the clock of the logger (see hclog.LoggerOptions.TimeFn)
+func (l Logger) now() time.Time {
+	if l.hcOpts.TimeFn != nil {
+		return l.hcOpts.TimeFn()
+	}
+	return time.Now()
+}
+
+// lineTime returns t or zero if the time is disabled (see HCL_TIME_FORMAT)
+// ECS lines keep the time: @timestamp is required
+func (l Logger) lineTime(t time.Time) time.Time {
+	if l.hcOpts.DisableTime && l.format != FormatECS {
+		return time.Time{}
+	}
+	return t
+}
+
+// encoder returns the encoder of the output, nil if hclog writes the lines
+func (l Logger) encoder() lineEncoder {
+	if l.format == FormatText && l.template != nil {
+		return l.template.appendLine
+	}
+	return l.format.encoder()
+}
+
+// sampled reports if a message of template is written by the sampler
+func (l Logger) sampled(level hclog.Level, template string) bool {
+	return l.sampler == nil || l.sampler.allow(l, level, template)
 }
 
 // SetLevel sets the log level
+// the level is shared with all related loggers (Named, ResetNamed, With)
+// but levels configured by SetLevels take precedence
 func (l *Logger) SetLevel(level hclog.Level) {
-	l.level = level
-	l.Logger.SetLevel(level)
+	l.base.Store(int32(level))
+}
+
+// StandardWriter returns a value that conforms to io.Writer,
+// which can be passed into log.SetOutput()
+func (l Logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	return newStdWriter(l, opts)
+}
+
+// StandardLogger returns a value that conforms to the stdlib log.Logger interface
+func (l Logger) StandardLogger(opts *hclog.StandardLoggerOptions) *gologger.Logger {
+	return gologger.New(l.StandardWriter(opts), "", 0)
+}
+
+// Flush waits until the lines written before are written to the output
+// only asynchronous outputs need to be flushed (see WithAsync)
+func (l Logger) Flush(ctx context.Context) error {
+	return flushWriter(ctx, l.w)
+}
+
+// Close flushes and closes the output and the sinks of l
+// stdout and stderr are never closed
+func (l Logger) Close() error {
+	return errors.Join(closeWriter(l.w), l.sinks.close())
 }
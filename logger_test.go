@@ -0,0 +1,66 @@
+package hcl
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabledNoAlloc(t *testing.T) {
+	keepDefault(t)
+	l := newLogger(WithWriter(io.Discard), WithLevel(hclog.Off), WithStdlib(false))
+	// constant arguments are boxed without allocation:
+	// any allocation is done by the logger
+	helpers := map[string]func(){
+		"Errorf":  func() { l.Errorf("hcl %d %s", 42, "str") },
+		"Warnf":   func() { l.Warnf("hcl %d %s", 42, "str") },
+		"Infof":   func() { l.Infof("hcl %d %s", 42, "str") },
+		"Debugf":  func() { l.Debugf("hcl %d %s", 42, "str") },
+		"Tracef":  func() { l.Tracef("hcl %d %s", 42, "str") },
+		"Printf":  func() { l.Printf("hcl %d %s", 42, "str") },
+		"Print":   func() { l.Print("hcl", 42, "str") },
+		"Println": func() { l.Println("hcl", 42, "str") },
+	}
+	for name, fn := range helpers {
+		if a := testing.AllocsPerRun(100, fn); a != 0 {
+			t.Errorf("%s allocates %v times on a disabled level", name, a)
+		}
+	}
+
+	// non-constant arguments are boxed by the caller: hot paths are guarded
+	n := 1000
+	guarded := func() {
+		n++
+		if l.IsDebug() {
+			l.Debug("hcl", "count", n)
+		}
+		if l.IsTrace() {
+			l.Tracef("hcl %d", n)
+		}
+	}
+	if a := testing.AllocsPerRun(100, guarded); a != 0 {
+		t.Errorf("guarded calls allocate %v times on a disabled level", a)
+	}
+}
+
+func TestEmbeddedHclogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLogger(WithName("embedded"), WithWriter(&buf), WithLevel(hclog.Info), WithStdlib(false), WithRedaction("password"))
+	// libraries get the embedded hclog.Logger
+	var lib hclog.Logger = l.Logger
+	assert.False(t, lib.IsDebug())
+	assert.True(t, lib.IsInfo())
+	lib.Debug("hidden")
+	lib.Named("lib").Info("shown", "password", "pw")
+	lib.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}).Print("[DEBUG] hidden std")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "embedded.lib: shown: password="+Redacted)
+
+	l.SetLevel(hclog.Debug)
+	assert.True(t, lib.IsDebug())
+	lib.SetLevel(hclog.Warn)
+	assert.Equal(t, hclog.Warn, l.GetLevel())
+}
@@ -0,0 +1,229 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// ErrNoRecorder is returned by DumpRecorder if the logger has no flight recorder
+var ErrNoRecorder = errors.New("logger has no flight recorder")
+
+// RecorderOpts configures the flight recorder of WithRecorder
+type RecorderOpts struct {
+	// Size is the number of lines kept (default 1000)
+	Size int
+	// Level is the lowest level recorded (default Trace)
+	// lines are recorded even if their level is not written
+	Level hclog.Level
+	// DumpLevel is the level that dumps the recorded lines (default Error)
+	// the lines not written since the last dump are written before the line
+	// Off disables the dumps, use DumpRecorder instead
+	DumpLevel hclog.Level
+}
+
+// WithRecorder is used to create a logger with a flight recorder
+// the recorder keeps the last lines of all levels in memory
+// and writes them as context when an error is logged (see RecorderOpts)
+// values which may change, e.g. maps or pointers, are formatted when the line is recorded
+// the recorder is shared with all related loggers (Named, ResetNamed, With)
+func WithRecorder(opts RecorderOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.recorder = newFlightRecorder(opts)
+	}
+}
+
+// recordEntry is a recorded line
+type recordEntry struct {
+	seq     uint64
+	time    time.Time
+	level   hclog.Level
+	name    string
+	msg     string
+	args    []interface{}
+	implied []interface{}
+	// written is set if the line was written to the output
+	written bool
+}
+
+// flightRecorder is a lock-free ring buffer of the last lines
+type flightRecorder struct {
+	opts RecorderOpts
+
+	// next is the sequence of the last recorded line
+	next  atomic.Uint64
+	slots []atomic.Pointer[recordEntry]
+	// dumped is the sequence of the last line dumped at DumpLevel
+	dumped atomic.Uint64
+	// dumpMu serializes the dumps, recording does not wait for it
+	dumpMu sync.Mutex
+}
+
+func newFlightRecorder(opts RecorderOpts) *flightRecorder {
+	if opts.Size < 1 {
+		opts.Size = 1000
+	}
+	if opts.Level == hclog.NoLevel {
+		opts.Level = hclog.Trace
+	}
+	if opts.DumpLevel == hclog.NoLevel {
+		opts.DumpLevel = hclog.Error
+	}
+	return &flightRecorder{
+		opts:  opts,
+		slots: make([]atomic.Pointer[recordEntry], opts.Size),
+	}
+}
+
+// records reports if lines of level are recorded
+func (r *flightRecorder) records(level hclog.Level) bool {
+	return level >= r.opts.Level && level != hclog.Off
+}
+
+// record adds a line to the ring buffer
+func (r *flightRecorder) record(l Logger, level hclog.Level, msg string, args []interface{}, written bool) {
+	if !r.records(level) {
+		return
+	}
+	e := &recordEntry{
+		time:    l.now(),
+		level:   level,
+		name:    l.name,
+		msg:     msg,
+		args:    snapshotArgs(args),
+		implied: snapshotArgs(l.backend().ImpliedArgs()),
+		written: written,
+	}
+	e.seq = r.next.Add(1)
+	r.slots[(e.seq-1)%uint64(len(r.slots))].Store(e)
+}
+
+// snapshotArgs copies the key/value pairs of a recorded line
+// values which may change later, e.g. maps or pointers, are formatted now
+// so the dump shows them as they were logged and does not race with the caller
+func snapshotArgs(args []interface{}) []interface{} {
+	if len(args) < 1 {
+		return nil
+	}
+	snap := make([]interface{}, len(args))
+	for i, v := range args {
+		switch val := v.(type) {
+		case nil, string, error, time.Time, time.Duration:
+			snap[i] = v
+		case hclog.Format:
+			if len(val) < 1 {
+				snap[i] = ""
+				break
+			}
+			snap[i] = fmt.Sprintf(fmt.Sprint(val[0]), val[1:]...)
+		default:
+			switch reflect.TypeOf(v).Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+				snap[i] = v
+			default:
+				snap[i] = fmt.Sprint(v)
+			}
+		}
+	}
+	return snap
+}
+
+// entries returns the recorded lines with a sequence above from
+// lines overwritten or not yet stored are skipped
+func (r *flightRecorder) entries(from uint64) ([]*recordEntry, uint64) {
+	end := r.next.Load()
+	start := from
+	if size := uint64(len(r.slots)); end > size && end-size > start {
+		start = end - size
+	}
+	entries := make([]*recordEntry, 0, end-start)
+	for seq := start + 1; seq <= end; seq++ {
+		if e := r.slots[(seq-1)%uint64(len(r.slots))].Load(); e != nil && e.seq == seq {
+			entries = append(entries, e)
+		}
+	}
+	return entries, end
+}
+
+// dumpsAt reports if a written line of level dumps the recorder
+func (r *flightRecorder) dumpsAt(level hclog.Level) bool {
+	return level >= r.opts.DumpLevel && r.opts.DumpLevel != hclog.Off
+}
+
+// dumpHidden writes the lines not written to the output since the last dump
+func (r *flightRecorder) dumpHidden(l Logger) {
+	r.dumpMu.Lock()
+	defer r.dumpMu.Unlock()
+	entries, end := r.entries(r.dumped.Load())
+	r.dumped.Store(end)
+	hidden := entries[:0]
+	for _, e := range entries {
+		if !e.written {
+			hidden = append(hidden, e)
+		}
+	}
+	if len(hidden) < 1 {
+		return
+	}
+	r.write(l, l.w, hidden)
+}
+
+// write formats the entries like the output of the logger
+func (r *flightRecorder) write(l Logger, w io.Writer, entries []*recordEntry) {
+	if enc := l.encoder(); enc != nil {
+		var buf []byte
+		line := func(t time.Time, name string, level hclog.Level, msg string, args []interface{}) {
+			buf = enc(buf[:0], t, name, level, msg, args)
+			_, _ = writeLevel(w, level, buf)
+		}
+		l.outMu.Lock()
+		defer l.outMu.Unlock()
+		line(l.lineTime(l.now()), l.name, hclog.Info, "flight recorder start", []interface{}{"lines", len(entries)})
+		for _, e := range entries {
+			line(l.lineTime(e.time), e.name, e.level, e.msg, e.allArgs())
+		}
+		line(l.lineTime(l.now()), l.name, hclog.Info, "flight recorder end", nil)
+		return
+	}
+	opts := *l.hcOpts
+	opts.Output = w
+	opts.Level = hclog.Trace
+	var now time.Time
+	opts.TimeFn = func() time.Time { return now }
+	backend := hclog.New(&opts)
+	now = l.now()
+	backend.Info("flight recorder start", "lines", len(entries))
+	for _, e := range entries {
+		now = e.time
+		backend.ResetNamed(e.name).Log(e.level, e.msg, e.allArgs()...)
+	}
+	now = l.now()
+	backend.Info("flight recorder end")
+}
+
+// allArgs returns the args of With followed by those of the line
+func (e *recordEntry) allArgs() []interface{} {
+	args := make([]interface{}, 0, len(e.implied)+len(e.args))
+	args = append(args, e.implied...)
+	return append(args, e.args...)
+}
+
+// DumpRecorder writes the lines of the flight recorder to w (see WithRecorder)
+func (l Logger) DumpRecorder(w io.Writer) error {
+	if l.recorder == nil {
+		return ErrNoRecorder
+	}
+	l.recorder.dumpMu.Lock()
+	defer l.recorder.dumpMu.Unlock()
+	entries, _ := l.recorder.entries(0)
+	l.recorder.write(l, w, entries)
+	return nil
+}
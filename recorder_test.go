@@ -0,0 +1,90 @@
+package hcl
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	keepDefault(t)
+	var buf bytes.Buffer
+	l := New(WithName("rec"), WithWriter(&buf), WithLevel(hclog.Warn), WithStdlib(false),
+		WithRecorder(RecorderOpts{Size: 4}),
+	)
+	assert.False(t, l.IsDebug())
+	l.Debugf("debug %d", 1)
+	l.Named("sub").With("conn", 7).Trace("trace", "try", 2)
+	l.Warn("warning")
+	l.Infof("info %d", 3)
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.NotContains(t, buf.String(), "debug")
+
+	l.Error("failed")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 6) {
+		assert.Contains(t, lines[0], "[WARN]  rec: warning")
+		assert.Contains(t, lines[1], "[INFO]  rec: flight recorder start: lines=2")
+		// debug 1 is overwritten by the ring
+		assert.Contains(t, lines[2], "[TRACE] rec.sub: trace: conn=7 try=2")
+		assert.Contains(t, lines[3], "[INFO]  rec: info 3")
+		assert.Contains(t, lines[4], "[INFO]  rec: flight recorder end")
+		assert.Contains(t, lines[5], "[ERROR] rec: failed")
+	}
+
+	// dumped lines are not dumped again
+	buf.Reset()
+	l.Error("failed again")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	var dump bytes.Buffer
+	assert.NoError(t, DumpRecorder(&dump))
+	assert.Contains(t, dump.String(), "rec: failed\n")
+	assert.Contains(t, dump.String(), "rec: failed again")
+	assert.Contains(t, dump.String(), "rec: info 3")
+
+	assert.ErrorIs(t, newLogger(WithWriter(&buf)).DumpRecorder(&dump), ErrNoRecorder)
+}
+
+func TestRecorderSnapshot(t *testing.T) {
+	keepDefault(t)
+	var buf bytes.Buffer
+	l := newLogger(WithWriter(&buf), WithLevel(hclog.Off), WithStdlib(false), WithRecorder(RecorderOpts{Size: 4}))
+	m := map[string]int{"a": 1}
+	n := 2
+	l.With("with", m).Info("logged", "map", m, "ptr", &n, "fmt", hclog.Fmt("%d", n))
+	m["a"] = 3
+	n = 4
+
+	var dump bytes.Buffer
+	assert.NoError(t, l.DumpRecorder(&dump))
+	assert.Contains(t, dump.String(), "with=map[a:1]")
+	assert.Contains(t, dump.String(), "map=map[a:1]")
+	assert.Contains(t, dump.String(), "fmt=2")
+	assert.NotContains(t, dump.String(), "a:3")
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	keepDefault(t)
+	var buf bytes.Buffer
+	l := newLogger(WithWriter(&buf), WithLevel(hclog.Off), WithStdlib(false), WithRecorder(RecorderOpts{Size: 100}))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 1000; n++ {
+				l.Debugf("line %d", n)
+			}
+		}()
+	}
+	wg.Wait()
+	entries, end := l.recorder.entries(0)
+	assert.Equal(t, uint64(8000), end)
+	assert.Len(t, entries, 100)
+	assert.Empty(t, buf.String())
+}
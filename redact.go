@@ -0,0 +1,246 @@
+package hcl
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive keys
+const Redacted = "[REDACTED]"
+
+// WithRedaction is used to create a logger which redacts the values of sensitive keys
+// keys are matched case insensitive and may contain wildcards like "*_secret" (see path.Match)
+// a dotted key like "request.password" matches by its last element as well
+// the values are redacted in key/value args, args of With and in maps and structs
+// (also those given to the printf helpers), values nested too deep are redacted as a whole
+// the redaction is inherited by Named, ResetNamed and With
+func WithRedaction(keys ...string) LoggerOpt {
+	return func(l *Logger) {
+		r := l.redactor.clone()
+		for _, k := range keys {
+			k = strings.ToLower(k)
+			if _, err := path.Match(k, ""); err != nil {
+				l.initErrs = append(l.initErrs, fmt.Errorf("ignoring redaction key %q: %w", k, err))
+				continue
+			}
+			r.keys = append(r.keys, k)
+		}
+		l.redactor = r
+	}
+}
+
+// WithRedactionRegexp is used to create a logger which redacts the values of keys matching a regexp
+// see WithRedaction
+func WithRedactionRegexp(res ...*regexp.Regexp) LoggerOpt {
+	return func(l *Logger) {
+		r := l.redactor.clone()
+		r.res = append(r.res, res...)
+		l.redactor = r
+	}
+}
+
+// redactor replaces the values of sensitive keys
+// it is not modified after the creation of the logger
+type redactor struct {
+	keys []string
+	res  []*regexp.Regexp
+}
+
+func (r *redactor) clone() *redactor {
+	n := &redactor{}
+	if r != nil {
+		n.keys = append(n.keys, r.keys...)
+		n.res = append(n.res, r.res...)
+	}
+	return n
+}
+
+// sensitive reports if the value of key has to be redacted
+func (r *redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	last := key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		last = key[i+1:]
+	}
+	for _, k := range r.keys {
+		if ok, _ := path.Match(k, key); ok {
+			return true
+		}
+		if ok, _ := path.Match(k, last); ok && last != key {
+			return true
+		}
+	}
+	for _, re := range r.res {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// args returns a copy of the key/value pairs with sensitive values redacted
+// args is returned unchanged if nothing is redacted
+func (r *redactor) args(args []interface{}) []interface{} {
+	var out []interface{}
+	for i := range args {
+		v, changed := r.value(args[i])
+		if i%2 == 1 {
+			if k, ok := args[i-1].(string); ok && r.sensitive(k) {
+				v, changed = Redacted, true
+			}
+		}
+		if changed && out == nil {
+			out = make([]interface{}, len(args))
+			copy(out, args)
+		}
+		if changed {
+			out[i] = v
+		}
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+// printf returns a copy of the printf args with maps and structs redacted
+// v is returned unchanged if nothing is redacted
+func (r *redactor) printf(v []interface{}) []interface{} {
+	var out []interface{}
+	for i := range v {
+		rv, changed := r.value(v[i])
+		if changed && out == nil {
+			out = make([]interface{}, len(v))
+			copy(out, v)
+		}
+		if changed {
+			out[i] = rv
+		}
+	}
+	if out == nil {
+		return v
+	}
+	return out
+}
+
+// value redacts sensitive keys of maps and fields of structs nested in v
+// and reports if anything was redacted
+// redacted maps and structs are replaced by a map[string]interface{}
+func (r *redactor) value(v interface{}) (interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	return r.walk(reflect.ValueOf(v), 0)
+}
+
+// maxRedactDepth limits the walk of nested values (and cycles)
+// nested values past the limit are redacted
+const maxRedactDepth = 10
+
+// walk returns the redacted value of v and if anything was redacted
+func (r *redactor) walk(v reflect.Value, depth int) (interface{}, bool) {
+	if depth > maxRedactDepth {
+		if nested(v.Type()) {
+			return Redacted, true
+		}
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return r.walk(v.Elem(), depth+1)
+	case reflect.Map:
+		m := make(map[string]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			k := mapKey(iter.Key())
+			if r.sensitive(k) {
+				m[k] = Redacted
+				changed = true
+				continue
+			}
+			if n, ok := r.walk(iter.Value(), depth+1); ok {
+				m[k] = n
+				changed = true
+				continue
+			}
+			m[k] = printable(iter.Value())
+		}
+		return m, changed
+	case reflect.Struct:
+		t := v.Type()
+		m := make(map[string]interface{}, v.NumField())
+		changed := false
+		for i := 0; i < v.NumField(); i++ {
+			f := t.Field(i)
+			k := f.Name
+			if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+				k = tag
+			}
+			if r.sensitive(f.Name) || r.sensitive(k) {
+				m[k] = Redacted
+				changed = true
+				continue
+			}
+			if n, ok := r.walk(v.Field(i), depth+1); ok {
+				m[k] = n
+				changed = true
+				continue
+			}
+			m[k] = printable(v.Field(i))
+		}
+		return m, changed
+	case reflect.Slice, reflect.Array:
+		if !nested(v.Type().Elem()) {
+			return nil, false
+		}
+		s := make([]interface{}, v.Len())
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			if n, ok := r.walk(v.Index(i), depth+1); ok {
+				s[i] = n
+				changed = true
+				continue
+			}
+			s[i] = printable(v.Index(i))
+		}
+		return s, changed
+	}
+	return nil, false
+}
+
+// mapKey returns the key of a map as string
+// keys which are not strings are formatted (e.g. by their String method)
+func mapKey(k reflect.Value) string {
+	for k.Kind() == reflect.Interface && !k.IsNil() {
+		k = k.Elem()
+	}
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	return fmt.Sprint(printable(k))
+}
+
+// nested reports if values of t may contain maps or structs
+func nested(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// printable returns the value of v
+// unexported values are formatted since they cannot be accessed
+func printable(v reflect.Value) interface{} {
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprint(v)
+}
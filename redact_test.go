@@ -0,0 +1,85 @@
+package hcl
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type redactUser struct {
+	Name     string
+	Password string `json:"pw"`
+	Creds    *redactCreds
+	token    []string
+}
+
+type redactCreds struct {
+	APISecret string `json:"api_secret"`
+	Region    string
+}
+
+func TestRedaction(t *testing.T) {
+	keepDefault(t)
+	var buf bytes.Buffer
+	l := newLogger(WithName("redact"), WithWriter(&buf), WithLevel(hclog.Trace), WithStdlib(false),
+		WithRedaction("password", "Token", "authorization", "*_secret"),
+		WithRedactionRegexp(regexp.MustCompile(`^x-.*-key$`)),
+	)
+	user := redactUser{Name: "joe", Password: "pw1", Creds: &redactCreds{APISecret: "s3", Region: "eu"}, token: []string{"t1"}}
+
+	l.Info("login", "user", "joe", "password", "pw2", "AUTHORIZATION", "Bearer b1", "x-api-key", "k1", "http.token", "t2")
+	l.With("db_secret", "s1").Named("sub").Error("with", "headers", map[string]string{"Authorization": "Bearer b2", "Accept": "*/*"})
+	l.Warn("struct", "user", user)
+	l.Infof("printf %v %+v", map[string]interface{}{"nested": map[string]string{"token": "t3"}}, &user)
+	l.Print("print ", []redactUser{user})
+	l.Slog().WithGroup("req").Info("slog", "password", "pw3")
+
+	out := buf.String()
+	for _, secret := range []string{"pw1", "pw2", "pw3", "Bearer", "k1", "s1", "s3", "t1", "t2", "t3"} {
+		assert.NotContains(t, out, secret)
+	}
+	for _, visible := range []string{"joe", "*/*", "eu", "redact.sub: with"} {
+		assert.Contains(t, out, visible)
+	}
+	assert.Contains(t, out, "password="+Redacted)
+	assert.Contains(t, out, "db_secret="+Redacted)
+	assert.Contains(t, out, "req.password="+Redacted)
+}
+
+// redactKey is a map key formatted by its String method
+type redactKey struct{ name string }
+
+func (k redactKey) String() string { return k.name }
+
+func TestRedactionFailClosed(t *testing.T) {
+	r := (*redactor)(nil).clone()
+	r.keys = []string{"password"}
+	// nested deeper than maxRedactDepth
+	var deep interface{} = map[string]interface{}{"password": "deep"}
+	for i := 0; i < maxRedactDepth; i++ {
+		deep = map[string]interface{}{"level": deep}
+	}
+	for _, v := range []interface{}{
+		deep,
+		map[interface{}]interface{}{"password": "iface", 1: "one"},
+		map[redactKey]string{{"Password"}: "stringer"},
+	} {
+		out := fmt.Sprint(r.args([]interface{}{"data", v}))
+		assert.Contains(t, out, Redacted)
+		for _, secret := range []string{"deep", "iface", "stringer"} {
+			assert.NotContains(t, out, secret)
+		}
+	}
+}
+
+func TestRedactionUnchanged(t *testing.T) {
+	r := (*redactor)(nil).clone()
+	r.keys = []string{"password"}
+	args := []interface{}{"user", "joe", "count", 1, "data", []byte("abc")}
+	out := r.args(args)
+	assert.Equal(t, &args[0], &out[0], "args without secrets must not be copied")
+}
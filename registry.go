@@ -0,0 +1,178 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// loggerEntry tracks the loggers of one name
+// all loggers with the same name share the entry
+// so short lived loggers (e.g. With per request) do not grow the registry
+// the entry is removed when the last logger of the name is collected
+type loggerEntry struct {
+	name string
+	// level is the level configured by SetLevels or hclog.NoLevel
+	level atomic.Int32
+	// base is the level of the logger last registered with the name
+	// (guarded by registryMu)
+	base *atomic.Int32
+	// logger is the logger last registered with the name without its reference
+	// (guarded by registryMu)
+	logger Logger
+	// created is the file:line the name was first used
+	created string
+	// w is the writer last set for the name (guarded by registryMu)
+	w io.Writer
+	// lines counts the lines written by the loggers of the name (see Stats)
+	// the counters outlive the entry
+	lines *lineCounters
+	// refs counts the references of the loggers (guarded by registryMu)
+	refs int
+}
+
+// loggerRef is the reference of loggers to their entry
+// the registry does not hold it: its finalizer removes the entry
+// when no logger of the name is left
+type loggerRef struct {
+	*loggerEntry
+}
+
+// configuredLevel returns the level configured by SetLevels or hclog.NoLevel
+func (e *loggerEntry) configuredLevel() hclog.Level {
+	return hclog.Level(e.level.Load())
+}
+
+// ref returns a new reference to the entry
+// registryMu must be held
+func (e *loggerEntry) ref() *loggerRef {
+	e.refs++
+	r := &loggerRef{e}
+	runtime.SetFinalizer(r, unref)
+	return r
+}
+
+// unref removes the entry of a collected reference if it was the last one
+func unref(r *loggerRef) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e := r.loggerEntry
+	e.refs--
+	if e.refs < 1 && registry[e.name] == e {
+		delete(registry, e.name)
+	}
+}
+
+var (
+	registryMu sync.RWMutex
+	// registry holds the entries of all logger names
+	registry = map[string]*loggerEntry{}
+)
+
+// register sets the registry entry of l
+// the entry is created if the name is new
+func register(l *Logger) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e, ok := registry[l.name]
+	if !ok {
+		e = &loggerEntry{name: l.name, created: callerSite(), lines: nameCounters(l.name)}
+		e.level.Store(int32(lookupLevel(l.name)))
+		registry[l.name] = e
+	}
+	e.base, e.w = l.base, l.w
+	l.entry = e.ref()
+	e.logger = *l
+	// the entry must not keep its references alive (also not by the adapter)
+	e.logger.entry = nil
+	e.logger.Logger = l.backend()
+}
+
+// setWriter records w as the writer of the loggers of the entry
+func (e *loggerEntry) setWriter(w io.Writer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e.w = w
+}
+
+// hclDir is the source directory of hcl
+var hclDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerSite returns the file:line of the first caller outside of hcl
+func callerSite() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if filepath.Dir(f.File) != hclDir || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// LoggerInfo describes the loggers of a name
+type LoggerInfo struct {
+	// Name of the logger
+	Name string
+	// Level is the effective level of the logger last created with the name
+	Level hclog.Level
+	// Configured is the level configured for the name by SetLevels
+	// or hclog.NoLevel
+	Configured hclog.Level
+	// Writer is the writer last set for the name
+	Writer io.Writer
+	// Created is the file:line the name was first used
+	Created string
+}
+
+// Loggers returns all logger names created by New, Named, ResetNamed
+// and LibraryLogger sorted by name
+// names of loggers which are no longer used are removed
+func Loggers() []LoggerInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	infos := make([]LoggerInfo, 0, len(registry))
+	for name, e := range registry {
+		lvl := e.configuredLevel()
+		if lvl == hclog.NoLevel {
+			lvl = hclog.Level(e.base.Load())
+		}
+		infos = append(infos, LoggerInfo{
+			Name:       name,
+			Level:      lvl,
+			Configured: e.configuredLevel(),
+			Writer:     e.w,
+			Created:    e.created,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Lookup returns the logger last created with name
+func Lookup(name string) (Logger, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e, ok := registry[name]
+	if !ok {
+		return Logger{}, false
+	}
+	l := e.logger
+	l.entry = e.ref()
+	l.setBackend(l.backend())
+	return l, true
+}
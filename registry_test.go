@@ -0,0 +1,113 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func findLogger(name string) *LoggerInfo {
+	for _, info := range Loggers() {
+		if info.Name == name {
+			return &info
+		}
+	}
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	keepDefault(t)
+	defer SetLevels("")
+	root := New(WithName("reg"), WithWriter(&buf), WithLevel(hclog.Info))
+	web := root.Named("web")
+	lib := LibraryLogger("lib")
+	other := root.ResetNamed("other")
+
+	info := findLogger("reg")
+	assert.NotNil(t, info)
+	assert.Equal(t, hclog.Info, info.Level)
+	assert.Equal(t, hclog.NoLevel, info.Configured)
+	assert.Equal(t, &buf, info.Writer)
+	assert.Contains(t, info.Created, "registry_test.go:")
+	assert.Contains(t, findLogger("reg.web").Created, "registry_test.go:")
+	assert.NotEqual(t, info.Created, findLogger("reg.web").Created)
+	assert.NotNil(t, findLogger("reg.lib"))
+	assert.NotNil(t, findLogger("other"))
+
+	l, ok := Lookup("reg.web")
+	assert.True(t, ok)
+	assert.Equal(t, web.Name(), l.Name())
+	l.Info("text to output")
+	assert.Equal(t, "[INFO]  reg.web: text to output\n", buf.Line())
+	_, ok = Lookup("does-not-exist")
+	assert.False(t, ok)
+
+	SetNamedLevel("reg.web", hclog.Trace)
+	info = findLogger("reg.web")
+	assert.Equal(t, hclog.Trace, info.Level)
+	assert.Equal(t, hclog.Trace, info.Configured)
+	assert.True(t, l.IsTrace())
+	assert.True(t, web.IsTrace())
+	assert.False(t, lib.IsTrace())
+	assert.False(t, other.IsTrace())
+
+	var out testWriter
+	other.SetWriter(&out)
+	assert.Equal(t, &out, findLogger("other").Writer)
+}
+
+func TestRegistryWith(t *testing.T) {
+	keepDefault(t)
+	root := New(WithName("with"), WithWriter(&buf), WithLevel(hclog.Info))
+	for i := 0; i < 100; i++ {
+		root.With("request", i).Named("req").With("id", fmt.Sprint(i)).Info("text to output")
+		buf.Reset()
+	}
+	names := 0
+	for _, info := range Loggers() {
+		if strings.HasPrefix(info.Name, "with") {
+			names++
+		}
+	}
+	assert.Equal(t, 2, names)
+}
+
+func TestRegistryCurrent(t *testing.T) {
+	keepDefault(t)
+	New(WithName("current"), WithWriter(&buf), WithLevel(hclog.Info))
+	last := New(WithName("current"), WithWriter(io.Discard), WithLevel(hclog.Error))
+	info := findLogger("current")
+	assert.Equal(t, hclog.Error, info.Level)
+	assert.Equal(t, io.Discard, info.Writer)
+	l, ok := Lookup("current")
+	assert.True(t, ok)
+	assert.Equal(t, hclog.Error, l.GetLevel())
+
+	last.SetLevel(hclog.Debug)
+	assert.Equal(t, hclog.Debug, findLogger("current").Level)
+}
+
+func TestRegistryRemove(t *testing.T) {
+	keepDefault(t)
+	root := newLogger(WithName("gone"), WithWriter(io.Discard), WithStdlib(false))
+	before := lineCount(Stats(), "gone.dynamic9", hclog.Error)
+	for i := 0; i < 10; i++ {
+		root.Named(fmt.Sprint("dynamic", i)).Error("counted")
+	}
+	assert.NotNil(t, findLogger("gone.dynamic0"))
+	// the names of collected loggers are removed
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return findLogger("gone.dynamic0") == nil && findLogger("gone.dynamic9") == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, findLogger("gone"))
+	// their lines are still counted
+	assert.Equal(t, before+1, lineCount(Stats(), "gone.dynamic9", hclog.Error))
+	runtime.KeepAlive(root)
+}
@@ -0,0 +1,155 @@
+package hcl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SampleRule writes the First messages of an interval and every Thereafter-th message after that
+// a rule with First 0 does not sample: all messages are written
+// Thereafter 0 suppresses all messages after First
+type SampleRule struct {
+	First      int
+	Thereafter int
+}
+
+// SampleOpts configures the sampling of WithSampling
+// messages are counted by logger name, level and message template
+// (the format of the printf helpers, the message of the key/value functions)
+type SampleOpts struct {
+	// Interval after which the counters are reset (default 1s)
+	// the number of suppressed messages is logged at the end of each interval
+	Interval time.Duration
+	// SampleRule is used for all levels and names not configured below
+	SampleRule
+	// Levels configures rules per level
+	Levels map[hclog.Level]SampleRule
+	// Names configures rules per logger name, the most specific name wins
+	// "myapp" applies to "myapp.web" as well, a name rule wins over a level rule
+	Names map[string]SampleRule
+}
+
+// WithSampling is used to create a logger which samples repetitive messages
+// the sampler is shared with all related loggers (Named, ResetNamed, With)
+func WithSampling(opts SampleOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.sampler = newSampler(opts)
+	}
+}
+
+// sampleKey identifies similar messages
+type sampleKey struct {
+	name     string
+	level    hclog.Level
+	template string
+}
+
+// sampleCount counts the messages of a sampleKey in the current interval
+type sampleCount struct {
+	seen       int
+	suppressed uint64
+	// logger writes the summary
+	logger Logger
+}
+
+// sampler decides which messages are written
+type sampler struct {
+	opts SampleOpts
+
+	mu     sync.Mutex
+	counts map[sampleKey]*sampleCount
+	// timer ends the interval, it runs while counts is not empty
+	timer *time.Timer
+}
+
+func newSampler(opts SampleOpts) *sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	return &sampler{
+		opts:   opts,
+		counts: make(map[sampleKey]*sampleCount),
+	}
+}
+
+// nameRule returns the rule of the most specific configured name
+// it is not cached: names may be dynamic and the lookup does not allocate
+func (s *sampler) nameRule(name string) (SampleRule, bool) {
+	if len(s.opts.Names) < 1 {
+		return SampleRule{}, false
+	}
+	for n := name; ; {
+		if r, ok := s.opts.Names[n]; ok {
+			return r, true
+		}
+		i := strings.LastIndex(n, ".")
+		if i < 0 {
+			return SampleRule{}, false
+		}
+		n = n[:i]
+	}
+}
+
+// rule returns the rule for a message of level logged by name
+// s.mu must be held
+func (s *sampler) rule(name string, level hclog.Level) SampleRule {
+	if r, ok := s.nameRule(name); ok {
+		return r
+	}
+	if r, ok := s.opts.Levels[level]; ok {
+		return r
+	}
+	return s.opts.SampleRule
+}
+
+// allow counts the message and reports if it is to be written
+func (s *sampler) allow(l Logger, level hclog.Level, template string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule := s.rule(l.name, level)
+	if rule.First < 1 {
+		return true
+	}
+	key := sampleKey{name: l.name, level: level, template: template}
+	c, ok := s.counts[key]
+	if !ok {
+		c = &sampleCount{logger: l}
+		s.counts[key] = c
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.opts.Interval, s.tick)
+		}
+	}
+	c.seen++
+	if c.seen <= rule.First {
+		return true
+	}
+	if rule.Thereafter > 0 && (c.seen-rule.First)%rule.Thereafter == 0 {
+		return true
+	}
+	c.suppressed++
+	sampledLines.Add(1)
+	return false
+}
+
+// tick ends the interval: the counters are reset and suppressed messages are reported
+func (s *sampler) tick() {
+	s.mu.Lock()
+	counts := s.counts
+	s.counts = make(map[sampleKey]*sampleCount)
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+	for key, c := range counts {
+		if c.suppressed < 1 {
+			continue
+		}
+		// emit bypasses level and sampling
+		c.logger.emit(key.level, fmt.Sprintf("suppressed %d similar messages", c.suppressed), []interface{}{"template", key.template})
+	}
+}
@@ -0,0 +1,77 @@
+package hcl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSampleLogger(t *testing.T, buf *bytes.Buffer, opts SampleOpts) *Logger {
+	keepDefault(t)
+	opts.Interval = time.Hour
+	return newLogger(WithName("sample"), WithWriter(buf), WithLevel(hclog.Trace), WithStdlib(false), WithSampling(opts))
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := newSampleLogger(t, &buf, SampleOpts{SampleRule: SampleRule{First: 2, Thereafter: 3}})
+	for i := 0; i < 10; i++ {
+		l.Errorf("connection %d failed", i)
+		l.Named("kv").Error("connection failed", "try", i)
+	}
+	out := buf.String()
+	for _, want := range []string{"connection 0 failed", "connection 1 failed", "connection 4 failed", "connection 7 failed"} {
+		assert.Contains(t, out, want)
+	}
+	assert.NotContains(t, out, "connection 2 failed")
+	assert.Equal(t, 8, strings.Count(out, "\n"))
+	assert.Equal(t, 4, strings.Count(out, "sample.kv: connection failed"))
+
+	buf.Reset()
+	l.sampler.tick()
+	out = buf.String()
+	assert.Contains(t, out, "[ERROR] sample: suppressed 6 similar messages: template=\"connection %d failed\"")
+	assert.Contains(t, out, "[ERROR] sample.kv: suppressed 6 similar messages: template=\"connection failed\"")
+
+	// counters are reset
+	buf.Reset()
+	l.Errorf("connection %d failed", 11)
+	assert.Contains(t, buf.String(), "connection 11 failed")
+	buf.Reset()
+	l.sampler.tick()
+	assert.Empty(t, buf.String())
+}
+
+func TestSamplingRules(t *testing.T) {
+	var buf bytes.Buffer
+	l := newSampleLogger(t, &buf, SampleOpts{
+		Levels: map[hclog.Level]SampleRule{hclog.Debug: {First: 1}},
+		Names:  map[string]SampleRule{"sample.db": {First: 2}},
+	})
+	db := l.Named("db").Named("conn")
+	for i := 0; i < 5; i++ {
+		l.Infof("info %d", i)
+		l.Debugf("debug %d", i)
+		db.Debugf("db %d", i)
+	}
+	out := buf.String()
+	assert.Equal(t, 5, strings.Count(out, "[INFO]"))
+	assert.Equal(t, 1, strings.Count(out, "sample: debug"))
+	assert.Equal(t, 2, strings.Count(out, "sample.db.conn: db"))
+}
+
+func TestSamplingNameRule(t *testing.T) {
+	s := newSampler(SampleOpts{Names: map[string]SampleRule{"app.db": {First: 2}}})
+	r, ok := s.nameRule("app.db.conn42")
+	assert.True(t, ok)
+	assert.Equal(t, 2, r.First)
+	_, ok = s.nameRule("app.web")
+	assert.False(t, ok)
+	// dynamic names are looked up without keeping or allocating anything
+	name := "app.db.conn42"
+	assert.Zero(t, testing.AllocsPerRun(100, func() { s.nameRule(name) }))
+}
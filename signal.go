@@ -0,0 +1,148 @@
+package hcl
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SignalControl changes the level and reopens the outputs of a logger on signals
+// signals not available on a platform are not handled
+//
+//	sc := &hcl.SignalControl{}
+//	hcl.New(hcl.WithSignalControl(sc))
+//	defer sc.Stop()
+type SignalControl struct {
+	// More steps the level one notch more verbose (default SIGUSR1)
+	More os.Signal
+	// Less steps the level one notch less verbose (default SIGUSR2)
+	Less os.Signal
+	// Reopen reopens outputs implementing Reopener (default SIGHUP)
+	Reopen os.Signal
+
+	mu   sync.Mutex
+	ch   chan os.Signal
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// WithSignalControl lets sc control the level and the output of the logger
+// the level is stepped by SetLevel (Warn→Info→Debug→Trace)
+// or by SetNamedLevel if the level of the name is configured
+func WithSignalControl(sc *SignalControl) LoggerOpt {
+	return func(l *Logger) {
+		l.signals = sc
+	}
+}
+
+// start handles the signals for l
+// a previous logger is no longer handled
+func (sc *SignalControl) start(l Logger) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.stop()
+	if sc.More == nil {
+		sc.More = defaultSignalMore
+	}
+	if sc.Less == nil {
+		sc.Less = defaultSignalLess
+	}
+	if sc.Reopen == nil {
+		sc.Reopen = defaultSignalReopen
+	}
+	var sigs []os.Signal
+	for _, sig := range []os.Signal{sc.More, sc.Less, sc.Reopen} {
+		if sig != nil {
+			sigs = append(sigs, sig)
+		}
+	}
+	if len(sigs) < 1 {
+		return
+	}
+	sc.ch = make(chan os.Signal, 1)
+	sc.done = make(chan struct{})
+	signal.Notify(sc.ch, sigs...)
+	sc.wg.Add(1)
+	go sc.run(l, sc.ch, sc.done)
+}
+
+func (sc *SignalControl) run(l Logger, ch <-chan os.Signal, done <-chan struct{}) {
+	defer sc.wg.Done()
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-ch:
+			sc.handle(l, sig)
+		}
+	}
+}
+
+func (sc *SignalControl) handle(l Logger, sig os.Signal) {
+	switch sig {
+	case sc.More:
+		stepLevel(l, -1, sig)
+	case sc.Less:
+		stepLevel(l, 1, sig)
+	case sc.Reopen:
+		err := errNotReopener
+		if r, ok := l.w.(Reopener); ok {
+			err = r.Reopen()
+		}
+		switch {
+		case errors.Is(err, errNotReopener):
+			l.emit(hclog.Warn, "log output cannot be reopened", []interface{}{"signal", sig})
+		case err != nil:
+			l.emit(hclog.Error, "cannot reopen log output", []interface{}{"signal", sig, "error", err})
+		}
+	}
+}
+
+// stepLevel changes the effective level of l by step between Trace and Error
+// if the level of the name of l is configured (see SetLevels) the configuration is changed
+func stepLevel(l Logger, step int, sig os.Signal) {
+	old := l.GetLevel()
+	lvl := old + hclog.Level(step)
+	if lvl < hclog.Trace {
+		lvl = hclog.Trace
+	}
+	if lvl > hclog.Error {
+		lvl = hclog.Error
+	}
+	if lvl == old {
+		return
+	}
+	registryMu.RLock()
+	configured := lookupLevel(l.name) != hclog.NoLevel
+	registryMu.RUnlock()
+	if configured {
+		SetNamedLevel(l.name, lvl)
+	} else {
+		l.SetLevel(lvl)
+	}
+	// bypass the level: the change has to be visible in any case
+	l.emit(hclog.Info, "log level changed", []interface{}{"from", old, "to", lvl, "signal", sig})
+}
+
+// Stop stops handling the signals
+func (sc *SignalControl) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.stop()
+}
+
+// stop stops handling the signals
+// sc.mu must be held
+func (sc *SignalControl) stop() {
+	if sc.ch == nil {
+		return
+	}
+	signal.Stop(sc.ch)
+	close(sc.done)
+	sc.wg.Wait()
+	sc.ch = nil
+	sc.done = nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package hcl
+
+import (
+	"os"
+)
+
+// the platform has no signals to control the logger by default
+var (
+	defaultSignalMore   os.Signal
+	defaultSignalLess   os.Signal
+	defaultSignalReopen os.Signal
+)
@@ -0,0 +1,140 @@
+//go:build unix
+
+package hcl_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+)
+
+func TestSignalControlLevel(t *testing.T) {
+	var buf syncBuffer
+	sc := &hcl.SignalControl{}
+	defer sc.Stop()
+	l := hcl.New(hcl.WithName("sig"), hcl.WithWriter(&buf), hcl.WithLevel(hclog.Warn), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	sub := l.Named("sub")
+
+	steps := []struct {
+		sig syscall.Signal
+		exp hclog.Level
+	}{
+		{syscall.SIGUSR1, hclog.Info},
+		{syscall.SIGUSR1, hclog.Debug},
+		{syscall.SIGUSR1, hclog.Trace},
+		{syscall.SIGUSR1, hclog.Trace},
+		{syscall.SIGUSR2, hclog.Debug},
+	}
+	for _, s := range steps {
+		assert.NoError(t, syscall.Kill(os.Getpid(), s.sig))
+		assert.Eventually(t, func() bool { return sub.GetLevel() == s.exp }, time.Second, 5*time.Millisecond, s.exp.String())
+	}
+	assert.Eventually(t, func() bool { return strings.Contains(buf.String(), "from=trace to=debug") }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, buf.String(), "[INFO]  sig: log level changed: from=warn to=info signal=\"user defined signal 1\"")
+	assert.Equal(t, 4, strings.Count(buf.String(), "log level changed"))
+}
+
+func TestSignalControlReopen(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sig.log")
+	t.Setenv(hcl.EnvOutput, file)
+	t.Setenv(hcl.EnvTimeFormat, "none")
+	sc := &hcl.SignalControl{More: syscall.SIGUSR2, Less: syscall.SIGUSR1}
+	defer sc.Stop()
+	l := hcl.New(hcl.WithName("sig"), hcl.WithLevel(hclog.Info), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	l.Info("before rotation")
+	assert.NoError(t, os.Rename(file, file+".1"))
+
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		l.Info("after rotation")
+		_, err := os.Stat(file)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	out, err := os.ReadFile(file + ".1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "[INFO]  sig: before rotation\n"))
+	out, err = os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(out), "[INFO]  sig: after rotation\n"))
+
+	// configured signals
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	assert.Eventually(t, func() bool { return l.IsDebug() }, time.Second, 5*time.Millisecond)
+}
+
+func TestSignalControlStop(t *testing.T) {
+	sc := &hcl.SignalControl{More: syscall.SIGUSR1}
+	l := hcl.New(hcl.WithName("sig"), hcl.WithWriter(&syncBuffer{}), hcl.WithLevel(hclog.Warn), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	sc.Stop()
+	sc.Stop()
+	// a stopped control does not change the level
+	// the signal is ignored by a second control to keep the process alive
+	keep := &hcl.SignalControl{More: syscall.SIGUSR1}
+	hcl.New(hcl.WithWriter(&syncBuffer{}), hcl.WithSignalControl(keep), hcl.WithStdlib(false))
+	defer keep.Stop()
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, hclog.Warn, l.GetLevel())
+}
+
+func TestSignalControlAsyncReopen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sig.log")
+	sc := &hcl.SignalControl{}
+	defer sc.Stop()
+	opts := hclog.LoggerOptions{DisableTime: true}
+	l := hcl.New(hcl.WithName("sig"), hcl.WithLevel(hclog.Info), hcl.WithLoggerOptions(&opts), hcl.WithFile(file, hcl.RotateOpts{}),
+		hcl.WithAsync(hcl.AsyncOpts{}), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	defer l.Close()
+	l.Info("before rotation")
+	assert.NoError(t, l.Flush(context.Background()))
+	assert.NoError(t, os.Rename(file, file+".1"))
+
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(file)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	l.Info("after rotation")
+	assert.NoError(t, l.Flush(context.Background()))
+	out, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "[INFO]  sig: after rotation\n", string(out))
+}
+
+func TestSignalControlNotReopenable(t *testing.T) {
+	var buf syncBuffer
+	sc := &hcl.SignalControl{}
+	defer sc.Stop()
+	hcl.New(hcl.WithName("sig"), hcl.WithWriter(&buf), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "[WARN]  sig: log output cannot be reopened: signal=hangup")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSignalControlNamedLevel(t *testing.T) {
+	defer hcl.SetLevels("")
+	var buf syncBuffer
+	sc := &hcl.SignalControl{}
+	defer sc.Stop()
+	assert.NoError(t, hcl.SetLevels("signamed=warn"))
+	l := hcl.New(hcl.WithName("signamed"), hcl.WithWriter(&buf), hcl.WithLevel(hclog.Error), hcl.WithSignalControl(sc), hcl.WithStdlib(false))
+	assert.Equal(t, hclog.Warn, l.GetLevel())
+
+	// the configured level is stepped
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool { return l.GetLevel() == hclog.Info }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, hclog.Info, hcl.NamedLevels()["signamed"])
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "log level changed: from=warn to=info")
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package hcl
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	defaultSignalMore   os.Signal = syscall.SIGUSR1
+	defaultSignalLess   os.Signal = syscall.SIGUSR2
+	defaultSignalReopen os.Signal = syscall.SIGHUP
+)
@@ -0,0 +1,213 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SinkOpts configures a sink added by WithSink or AddSink
+type SinkOpts struct {
+	// Level is the lowest level sent to the sink
+	// the sink gets the lines of levels not written to the output of the logger
+	// if NoLevel, the sink gets the lines written to the output
+	Level hclog.Level
+	// Filter drops the lines for which it returns false
+	Filter func(name string, level hclog.Level, msg string) bool
+}
+
+// WithSink is used to create a logger which also writes to sink
+// see AddSink
+func WithSink(sink hclog.SinkAdapter, opts SinkOpts) LoggerOpt {
+	return func(l *Logger) {
+		if l.sinks == nil {
+			l.sinks = newSinkSet(nil)
+		}
+		l.sinks.add(sink, opts)
+	}
+}
+
+// AddSink adds a sink receiving the lines of the logger and its sub-loggers
+// sinks added to a sub-logger (Named, ResetNamed) are not used by its parent
+func (l Logger) AddSink(sink hclog.SinkAdapter, opts SinkOpts) {
+	l.sinks.add(sink, opts)
+}
+
+// RemoveSink removes a sink added to the logger and reports if it was found
+// the sink is not closed
+func (l Logger) RemoveSink(sink hclog.SinkAdapter) bool {
+	return l.sinks.remove(sink)
+}
+
+// sinkEntry is a sink with its options
+type sinkEntry struct {
+	sink hclog.SinkAdapter
+	opts SinkOpts
+}
+
+// accepts checks if the sink takes a line of level
+// out is set if the line is written to the output
+func (e sinkEntry) accepts(name string, level hclog.Level, msg string, out bool) bool {
+	if e.opts.Level == hclog.NoLevel {
+		if !out {
+			return false
+		}
+	} else if level < e.opts.Level || level == hclog.Off {
+		return false
+	}
+	return e.opts.Filter == nil || e.opts.Filter(name, level, msg)
+}
+
+// sinkSet holds the sinks of a logger, its With loggers and its sub-loggers
+// a sub-logger gets its own set with the set of its parent as parent
+type sinkSet struct {
+	parent *sinkSet
+
+	// mu serializes the changes, the lines are written without lock
+	mu    sync.Mutex
+	sinks atomic.Pointer[[]sinkEntry]
+	// min is the lowest level of the leveled sinks
+	min atomic.Int32
+}
+
+// newSinkSet creates a set with the sinks of parent
+func newSinkSet(parent *sinkSet) *sinkSet {
+	s := &sinkSet{parent: parent}
+	s.min.Store(math.MaxInt32)
+	return s
+}
+
+// add adds a sink
+func (s *sinkSet) add(sink hclog.SinkAdapter, opts SinkOpts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sinks := append(s.own(), sinkEntry{sink: sink, opts: opts})
+	s.store(sinks)
+}
+
+// remove removes a sink
+func (s *sinkSet) remove(sink hclog.SinkAdapter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sinks := s.own()
+	for i, e := range sinks {
+		if e.sink == sink {
+			s.store(append(sinks[:i], sinks[i+1:]...))
+			return true
+		}
+	}
+	return false
+}
+
+// own returns a copy of the sinks of s without those of the parents
+func (s *sinkSet) own() []sinkEntry {
+	var sinks []sinkEntry
+	if old := s.sinks.Load(); old != nil {
+		sinks = append(sinks, *old...)
+	}
+	return sinks
+}
+
+// store sets the sinks and their lowest level
+// s.mu must be held
+func (s *sinkSet) store(sinks []sinkEntry) {
+	min := int32(math.MaxInt32)
+	for _, e := range sinks {
+		if e.opts.Level != hclog.NoLevel && int32(e.opts.Level) < min {
+			min = int32(e.opts.Level)
+		}
+	}
+	s.sinks.Store(&sinks)
+	s.min.Store(min)
+}
+
+// wants reports if a leveled sink takes lines of level
+func (s *sinkSet) wants(level hclog.Level) bool {
+	for ; s != nil; s = s.parent {
+		if int32(level) >= s.min.Load() && level != hclog.Off {
+			return true
+		}
+	}
+	return false
+}
+
+// accept writes the line to all sinks taking it
+// out is set if the line is written to the output
+func (s *sinkSet) accept(l Logger, level hclog.Level, msg string, args []interface{}, out bool) {
+	implied := false
+	for ; s != nil; s = s.parent {
+		sinks := s.sinks.Load()
+		if sinks == nil {
+			continue
+		}
+		for _, e := range *sinks {
+			if !e.accepts(l.name, level, msg, out) {
+				continue
+			}
+			if !implied {
+				implied = true
+				args = withImplied(l, args)
+			}
+			e.sink.Accept(l.name, level, msg, args...)
+		}
+	}
+}
+
+// withImplied returns the args with those of With prepended
+func withImplied(l Logger, args []interface{}) []interface{} {
+	imp := l.backend().ImpliedArgs()
+	if len(imp) < 1 {
+		return args
+	}
+	all := make([]interface{}, 0, len(imp)+len(args))
+	all = append(all, imp...)
+	return append(all, args...)
+}
+
+// close closes all sinks of s implementing io.Closer
+func (s *sinkSet) close() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	for _, e := range s.own() {
+		if c, ok := e.sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stringPairs formats the key/value pairs as strings
+// errors are formatted by Error, hclog.Format by Sprintf
+func stringPairs(args []interface{}) [][2]string {
+	pairs := make([][2]string, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		k := fmt.Sprint(args[i])
+		if i+1 >= len(args) {
+			pairs = append(pairs, [2]string{"EXTRA_VALUE_AT_END", k})
+			break
+		}
+		var v string
+		switch val := args[i+1].(type) {
+		case error:
+			v = val.Error()
+		case hclog.Format:
+			if len(val) < 1 {
+				break
+			}
+			v = fmt.Sprintf(fmt.Sprint(val[0]), val[1:]...)
+		default:
+			v = fmt.Sprint(val)
+		}
+		pairs = append(pairs, [2]string{k, v})
+	}
+	return pairs
+}
@@ -0,0 +1,93 @@
+package hcl
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSink records the lines accepted
+type testSink struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *testSink) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sb strings.Builder
+	sb.WriteString(level.String() + " " + name + ": " + msg)
+	for _, p := range stringPairs(args) {
+		sb.WriteString(" " + p[0] + "=" + p[1])
+	}
+	s.lines = append(s.lines, sb.String())
+}
+
+func (s *testSink) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+func TestSinks(t *testing.T) {
+	keepDefault(t)
+	var out, file bytes.Buffer
+	errs := &testSink{}
+	l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Info), WithStdlib(false),
+		WithSink(NewWriterSink(&file, WriterSinkOpts{Format: FormatJSON}), SinkOpts{Level: hclog.Debug}),
+		WithSink(errs, SinkOpts{Level: hclog.Error}),
+	)
+	assert.False(t, l.IsDebug())
+	l.Debug("debug line", "k", 1)
+	l.With("req", 2).Info("info line")
+	l.Error("error line")
+	l.Trace("trace line")
+
+	assert.Equal(t, 2, strings.Count(out.String(), "\n"))
+	assert.NotContains(t, out.String(), "debug line")
+	assert.Equal(t, 3, strings.Count(file.String(), "\n"))
+	assert.Contains(t, file.String(), `"msg":"debug line"`)
+	assert.Contains(t, file.String(), `"req":2`)
+	assert.Equal(t, []string{"error app: error line"}, errs.Lines())
+
+	// sub-loggers inherit the sinks, their own sinks are not used by the parent
+	all := &testSink{}
+	web := l.Named("web")
+	web.AddSink(all, SinkOpts{Filter: func(name string, level hclog.Level, msg string) bool {
+		return !strings.Contains(msg, "health")
+	}})
+	web.With("conn", 3).Warn("web line")
+	web.Info("health check")
+	l.Warn("app line")
+	assert.Equal(t, []string{"warn app.web: web line conn=3"}, all.Lines())
+	assert.Len(t, errs.Lines(), 1)
+
+	// removed at runtime
+	assert.True(t, l.RemoveSink(errs))
+	assert.False(t, l.RemoveSink(errs))
+	web.Error("after remove")
+	assert.Len(t, errs.Lines(), 1)
+	assert.Len(t, all.Lines(), 2)
+	assert.False(t, web.IsTrace())
+	web.Trace("not enabled")
+	assert.Len(t, all.Lines(), 2)
+}
+
+func TestWriterSinkLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWriterSink(&buf, WriterSinkOpts{Format: FormatLogfmt})
+	s.now = func() time.Time { return time.Date(2022, 2, 25, 9, 40, 12, 123000000, time.UTC) }
+	s.Accept("app.web", hclog.Warn, `say "hi"`, "user", "joe", "empty", "", "a key", "x=y", "dangling")
+	assert.Equal(t, `time=2022-02-25T09:40:12.123Z level=warn logger=app.web msg="say \"hi\"" user=joe empty="" a_key="x=y" EXTRA_VALUE_AT_END=dangling`+"\n", buf.String())
+
+	buf.Reset()
+	s = NewWriterSink(&buf, WriterSinkOpts{})
+	s.now = func() time.Time { return time.Date(2022, 2, 25, 9, 40, 12, 0, time.UTC) }
+	s.Accept("app", hclog.Info, "text", "k", 1)
+	assert.Equal(t, "2022/02/25 09:40:12 [INFO]  app: text: k=1\n", buf.String())
+}
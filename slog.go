@@ -0,0 +1,100 @@
+package hcl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SlogHandler is a slog.Handler writing to a hcl Logger
+type SlogHandler struct {
+	hcl Logger
+	// prefix of the keys (dotted groups)
+	prefix string
+}
+
+// NewSlogHandler creates a slog.Handler which logs to l
+func NewSlogHandler(l Logger) *SlogHandler {
+	return &SlogHandler{hcl: l}
+}
+
+// Slog returns a slog.Logger which logs to l
+func (l Logger) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+// SlogLevel converts a slog level to the corresponding hclog level
+// levels below slog.LevelDebug are mapped to Trace
+func SlogLevel(level slog.Level) hclog.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return hclog.Trace
+	case level < slog.LevelInfo:
+		return hclog.Debug
+	case level < slog.LevelWarn:
+		return hclog.Info
+	case level < slog.LevelError:
+		return hclog.Warn
+	default:
+		return hclog.Error
+	}
+}
+
+// Enabled reports whether the hcl logger writes or records at level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.hcl.enabled(SlogLevel(level))
+}
+
+// Handle logs the record with the attributes as key/value pairs
+// the fields and trace ids of the context are added (see WithContext)
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := contextArgs(ctx)
+	args := make([]interface{}, 0, len(fields)+2*r.NumAttrs())
+	args = append(args, fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		args = appendAttr(args, h.prefix, a)
+		return true
+	})
+	h.hcl.Log(SlogLevel(r.Level), r.Message, args...)
+	return nil
+}
+
+// WithAttrs returns a handler whose logger has the attributes added by Logger.With
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	args := make([]interface{}, 0, 2*len(attrs))
+	for _, a := range attrs {
+		args = appendAttr(args, h.prefix, a)
+	}
+	if len(args) < 1 {
+		return h
+	}
+	return &SlogHandler{hcl: h.hcl.With(args...), prefix: h.prefix}
+}
+
+// WithGroup returns a handler which prefixes all following keys with name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{hcl: h.hcl, prefix: h.prefix + name + "."}
+}
+
+// appendAttr appends a as key/value pair to args
+// groups are flattened into dotted keys
+func appendAttr(args []interface{}, prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return args
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return append(args, prefix+a.Key, a.Value.Any())
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		args = appendAttr(args, prefix, ga)
+	}
+	return args
+}
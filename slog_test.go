@@ -0,0 +1,63 @@
+package hcl
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		slog slog.Level
+		hcl  hclog.Level
+	}{
+		{slog.LevelDebug - 4, hclog.Trace},
+		{slog.LevelDebug, hclog.Debug},
+		{slog.LevelDebug + 1, hclog.Debug},
+		{slog.LevelInfo, hclog.Info},
+		{slog.LevelWarn, hclog.Warn},
+		{slog.LevelError, hclog.Error},
+		{slog.LevelError + 4, hclog.Error},
+	}
+	for _, tc := range tests {
+		t.Run(tc.slog.String(), func(t *testing.T) {
+			assert.Equal(t, tc.hcl, SlogLevel(tc.slog))
+		})
+	}
+}
+
+func TestSlog(t *testing.T) {
+	l := New(WithName("slog"), WithLevel(hclog.Info), WithWriter(&buf))
+	sl := l.Slog()
+	ctx := context.Background()
+
+	assert.False(t, sl.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, sl.Enabled(ctx, slog.LevelInfo))
+	sl.Debug("not visible")
+	assert.Equal(t, 0, buf.Len())
+
+	sl.Info("text to output", "intParam", 42)
+	assert.Equal(t, "[INFO]  slog: text to output: intParam=42\n", buf.Line())
+	sl.Warn("text to output")
+	assert.Equal(t, "[WARN]  slog: text to output\n", buf.Line())
+	sl.Error("text to output", slog.Group("req", "id", 7, slog.Group("user", "name", "bob")))
+	assert.Equal(t, "[ERROR] slog: text to output: req.id=7 req.user.name=bob\n", buf.Line())
+
+	sl.With("arg", "some information").Info("with args")
+	assert.Equal(t, "[INFO]  slog: with args: arg=\"some information\"\n", buf.Line())
+
+	gl := sl.WithGroup("web").With("method", "GET").WithGroup("resp")
+	gl.Info("grouped", "status", 200)
+	assert.Equal(t, "[INFO]  slog: grouped: web.method=GET web.resp.status=200\n", buf.Line())
+
+	l.SetLevel(hclog.Trace)
+	assert.True(t, sl.Enabled(ctx, slog.LevelDebug-4))
+	sl.Log(ctx, slog.LevelDebug-4, "trace output")
+	assert.Equal(t, "[TRACE] slog: trace output\n", buf.Line())
+
+	l.Named("sub").Slog().Info("named")
+	assert.Equal(t, "[INFO]  slog.sub: named\n", buf.Line())
+}
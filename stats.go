@@ -0,0 +1,125 @@
+package hcl
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Statistics are the counters of all hcl loggers since the start of the process
+type Statistics struct {
+	// Lines are the lines written to the output per logger name and level
+	// sorted by name and level, levels without lines are included
+	// the counters of names no longer used are kept (unlike the names of Loggers)
+	Lines []LineStats
+	// Dropped is the number of lines dropped by outputs and sinks (e.g. WithAsync, syslog, journald)
+	// and by disconnected syslog and journald sinks (see WithSyslog, WithJournald)
+	Dropped uint64
+	// Sampled is the number of lines suppressed by sampling (see WithSampling)
+	Sampled uint64
+	// WriteErrors is the number of lines the output failed to write
+	WriteErrors uint64
+}
+
+// LineStats is the number of lines of a logger name and level
+type LineStats struct {
+	Name  string
+	Level hclog.Level
+	Count uint64
+}
+
+// Total returns the number of lines of level written by all loggers
+func (s Statistics) Total(level hclog.Level) uint64 {
+	var n uint64
+	for _, ls := range s.Lines {
+		if ls.Level == level {
+			n += ls.Count
+		}
+	}
+	return n
+}
+
+// statsLevels are the levels counted
+var statsLevels = []hclog.Level{hclog.Trace, hclog.Debug, hclog.Info, hclog.Warn, hclog.Error}
+
+// the process wide counters not kept per name
+var (
+	droppedLines atomic.Uint64
+	sampledLines atomic.Uint64
+	writeErrors  atomic.Uint64
+)
+
+// lineCounters count the lines of a logger name per level
+type lineCounters [hclog.Error + 1]atomic.Uint64
+
+// add counts a line of level
+func (c *lineCounters) add(level hclog.Level) {
+	if level >= hclog.Trace && level <= hclog.Error {
+		c[level].Add(1)
+	}
+}
+
+var (
+	statsMu sync.RWMutex
+	// lineStats are the line counters by logger name
+	// unlike the registry entries they are never removed: counters only go up
+	lineStats = map[string]*lineCounters{}
+)
+
+// nameCounters returns the line counters of name
+func nameCounters(name string) *lineCounters {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	c, ok := lineStats[name]
+	if !ok {
+		c = &lineCounters{}
+		lineStats[name] = c
+	}
+	return c
+}
+
+// Stats returns the counters of all loggers
+func Stats() Statistics {
+	statsMu.RLock()
+	names := make([]string, 0, len(lineStats))
+	counters := make(map[string]*lineCounters, len(lineStats))
+	for name, c := range lineStats {
+		names = append(names, name)
+		counters[name] = c
+	}
+	statsMu.RUnlock()
+	sort.Strings(names)
+	s := Statistics{
+		Lines:       make([]LineStats, 0, len(names)*len(statsLevels)),
+		Dropped:     droppedLines.Load(),
+		Sampled:     sampledLines.Load(),
+		WriteErrors: writeErrors.Load(),
+	}
+	for _, name := range names {
+		for _, level := range statsLevels {
+			s.Lines = append(s.Lines, LineStats{Name: name, Level: level, Count: counters[name][level].Load()})
+		}
+	}
+	return s
+}
+
+// statsWriter counts the write errors of the output written by hclog
+type statsWriter struct {
+	w io.Writer
+}
+
+func (sw statsWriter) Write(p []byte) (int, error) {
+	return sw.LevelWrite(hclog.Info, p)
+}
+
+// LevelWrite implements hclog.LevelWriter
+func (sw statsWriter) LevelWrite(level hclog.Level, p []byte) (int, error) {
+	n, err := writeLevel(sw.w, level, p)
+	if err != nil {
+		writeErrors.Add(1)
+	}
+	return n, err
+}
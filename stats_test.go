@@ -0,0 +1,75 @@
+package hcl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// failWriter fails all writes
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// lineCount returns the count of name and level in s
+func lineCount(s Statistics, name string, level hclog.Level) uint64 {
+	for _, ls := range s.Lines {
+		if ls.Name == name && ls.Level == level {
+			return ls.Count
+		}
+	}
+	return 0
+}
+
+func TestStatsLines(t *testing.T) {
+	l := newLogger(WithName("stats"), WithWriter(io.Discard), WithLevel(hclog.Info), WithStdlib(false))
+	before := Stats()
+	l.Info("one")
+	l.With("k", 1).Warn("two")
+	l.Named("web").Errorf("three")
+	l.Debug("not written")
+	s := Stats()
+	// the counters are process wide
+	delta := func(name string, level hclog.Level) uint64 {
+		return lineCount(s, name, level) - lineCount(before, name, level)
+	}
+	assert.Equal(t, uint64(1), delta("stats", hclog.Info))
+	assert.Equal(t, uint64(1), delta("stats", hclog.Warn))
+	assert.Equal(t, uint64(0), delta("stats", hclog.Debug))
+	assert.Equal(t, uint64(1), delta("stats.web", hclog.Error))
+	assert.Equal(t, before.Total(hclog.Error)+1, s.Total(hclog.Error))
+}
+
+func TestStatsCounters(t *testing.T) {
+	before := Stats()
+	l := newLogger(WithName("stats.sampled"), WithWriter(io.Discard), WithLevel(hclog.Info), WithStdlib(false),
+		// the summary of the interval is not part of the test
+		WithSampling(SampleOpts{Interval: time.Hour, SampleRule: SampleRule{First: 1}}))
+	l.Info("same")
+	l.Info("same")
+	l.Info("same")
+	assert.Equal(t, before.Sampled+2, Stats().Sampled)
+
+	for _, f := range []Format{FormatText, FormatJSON} {
+		before = Stats()
+		l = newLogger(WithName("stats.failed"), WithWriter(failWriter{}), WithLevel(hclog.Info), WithStdlib(false), WithFormat(f))
+		l.Info("lost")
+		assert.Equal(t, before.WriteErrors+1, Stats().WriteErrors, f.String())
+	}
+
+	before = Stats()
+	gw := newGateWriter()
+	aw := newAsyncWriter(gw, AsyncOpts{QueueSize: 2, Policy: OverflowDropNewest, ReportInterval: -1})
+	fillQueue(t, aw, gw)
+	aw.LevelWrite(hclog.Error, []byte("4\n"))
+	close(gw.gate)
+	assert.NoError(t, aw.Flush(context.Background()))
+	assert.Equal(t, before.Dropped+1, Stats().Dropped)
+}
@@ -0,0 +1,125 @@
+package hcl
+
+import (
+	"bytes"
+	"io"
+	gologger "log"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// stdlibState holds the log and slog defaults replaced by hcl
+type stdlibState struct {
+	w      io.Writer
+	prefix string
+	flags  int
+	slog   *slog.Logger
+}
+
+var (
+	stdlibMu    sync.Mutex
+	stdlibSaved *stdlibState
+)
+
+// redirectStdlib sets the std lib logger and the slog default to write to l
+// if l was created to capture them
+// stdlibMu must be held: it is swapped together with the default logger
+func (l *Logger) redirectStdlib() {
+	if !l.captureStdlib {
+		return
+	}
+	if stdlibSaved == nil {
+		stdlibSaved = &stdlibState{
+			w:      gologger.Writer(),
+			prefix: gologger.Prefix(),
+			flags:  gologger.Flags(),
+			slog:   slog.Default(),
+		}
+	}
+	// slog.SetDefault redirects the std lib logger to the slog handler
+	// so it has to be called before we set the std lib output
+	slog.SetDefault(l.Slog())
+	gologger.SetOutput(l.GetWriter())
+	gologger.SetPrefix("")
+	gologger.SetFlags(0)
+}
+
+// RestoreStdlib puts back the std lib log and slog defaults
+// which were active before hcl redirected them
+// the hcl default logger is not changed
+func RestoreStdlib() {
+	stdlibMu.Lock()
+	defer stdlibMu.Unlock()
+	if stdlibSaved == nil {
+		return
+	}
+	// restore slog first: it might change the std lib output
+	slog.SetDefault(stdlibSaved.slog)
+	gologger.SetOutput(stdlibSaved.w)
+	gologger.SetPrefix(stdlibSaved.prefix)
+	gologger.SetFlags(stdlibSaved.flags)
+	stdlibSaved = nil
+}
+
+// timestampRegexp matches characters commonly found at the beginning
+// of a line when the std lib log writes a timestamp
+var timestampRegexp = regexp.MustCompile(`^[\d\s\:\/\.\+-TZ]*`)
+
+// stdWriter shims the data of a std lib logger back into a Logger
+// it supports the options of hclog.StandardLoggerOptions
+type stdWriter struct {
+	hcl  Logger
+	opts hclog.StandardLoggerOptions
+}
+
+func newStdWriter(l Logger, opts *hclog.StandardLoggerOptions) *stdWriter {
+	w := &stdWriter{hcl: l}
+	if opts != nil {
+		w.opts = *opts
+	}
+	return w
+}
+
+// Write logs a line written by the std lib logger
+func (w *stdWriter) Write(data []byte) (int, error) {
+	str := string(bytes.TrimRight(data, " \t\n"))
+	switch {
+	case w.opts.ForceLevel != hclog.NoLevel:
+		_, str = pickLevel(str)
+		w.hcl.Log(w.opts.ForceLevel, str)
+	case w.opts.InferLevels:
+		if w.opts.InferLevelsWithTimestamp {
+			str = str[timestampRegexp.FindStringIndex(str)[1]:]
+		}
+		level, str := pickLevel(str)
+		w.hcl.Log(level, str)
+	default:
+		w.hcl.Info(str)
+	}
+	return len(data), nil
+}
+
+// pickLevel detects the level of a line by its prefix
+// and strips the prefix
+func pickLevel(str string) (hclog.Level, string) {
+	switch {
+	case strings.HasPrefix(str, "[DEBUG]"):
+		return hclog.Debug, strings.TrimSpace(str[7:])
+	case strings.HasPrefix(str, "[TRACE]"):
+		return hclog.Trace, strings.TrimSpace(str[7:])
+	case strings.HasPrefix(str, "[INFO]"):
+		return hclog.Info, strings.TrimSpace(str[6:])
+	case strings.HasPrefix(str, "[WARN]"):
+		return hclog.Warn, strings.TrimSpace(str[6:])
+	case strings.HasPrefix(str, "[ERROR]"):
+		return hclog.Error, strings.TrimSpace(str[7:])
+	case strings.HasPrefix(str, "[ERR]"):
+		return hclog.Error, strings.TrimSpace(str[5:])
+	default:
+		return hclog.Info, str
+	}
+}
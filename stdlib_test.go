@@ -0,0 +1,41 @@
+package hcl
+
+import (
+	gologger "log"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogDefault(t *testing.T) {
+	RestoreStdlib()
+	origSlog := slog.Default()
+	origWriter := gologger.Writer()
+	origFlags := gologger.Flags()
+
+	New(WithName("std"), WithLevel(hclog.Debug), WithWriter(&buf))
+	slog.Info("text to output", "strParam", "someParam", "intParam", 42)
+	assert.Equal(t, "[INFO]  std: text to output: strParam=someParam intParam=42\n", buf.Line())
+	slog.With("arg", 1).WithGroup("grp").Warn("text to output", "key", "val")
+	assert.Equal(t, "[WARN]  std: text to output: arg=1 grp.key=val\n", buf.Line())
+	slog.Debug("debug output")
+	assert.Equal(t, "[DEBUG] std: debug output\n", buf.Line())
+	gologger.Print("[ERROR] text to output")
+	assert.Equal(t, "[ERROR] std: text to output\n", buf.Line())
+
+	RestoreStdlib()
+	assert.Equal(t, origSlog, slog.Default())
+	assert.Equal(t, origWriter, gologger.Writer())
+	assert.Equal(t, origFlags, gologger.Flags())
+	slog.Info("not to hcl")
+	gologger.Print("not to hcl")
+	assert.Equal(t, 0, buf.Len())
+	// the hcl default logger is kept
+	Info("text to output")
+	assert.Equal(t, "[INFO]  std: text to output\n", buf.Line())
+
+	New(WithName("nostd"), WithWriter(&buf), WithStdlib(false))
+	assert.Equal(t, origSlog, slog.Default())
+}
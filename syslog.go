@@ -0,0 +1,348 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SyslogFormat is the frame format of syslog messages
+type SyslogFormat int
+
+const (
+	// RFC5424 frames carry the key/value pairs as structured data
+	RFC5424 SyslogFormat = iota
+	// RFC3164 (BSD) frames append the key/value pairs to the message
+	RFC3164
+)
+
+// SyslogOpts configures the syslog output of WithSyslog
+type SyslogOpts struct {
+	// Network is "udp", "tcp", "unix" or "unixgram"
+	// the local syslog (/dev/log) is used if empty
+	// tcp frames are octet counted, unix stream frames end at a newline (newlines of messages are escaped)
+	Network string
+	// Addr is the address of the syslog server or the socket path
+	Addr string
+	// Format of the frames (default RFC5424)
+	Format SyslogFormat
+	// Facility is the syslog facility (default 1: user)
+	Facility int
+	// AppName is the APP-NAME (TAG for RFC3164) of all messages
+	// if empty the logger name is used, otherwise the logger name
+	// is sent as structured data param "logger"
+	AppName string
+	// Hostname of the messages (default os.Hostname)
+	Hostname string
+	// SDID is the id of the structured data element (default "hcl@32473")
+	SDID string
+}
+
+// WithSyslog is used to create a logger also writing to syslog
+// lines written by the logger are sent as syslog messages
+// connection errors are logged at creation
+func WithSyslog(opts SyslogOpts) LoggerOpt {
+	return func(l *Logger) {
+		l.outputSet = true
+		s, err := NewSyslog(opts)
+		if err != nil {
+			l.initErrs = append(l.initErrs, fmt.Errorf("cannot connect syslog: %w", err))
+			return
+		}
+		if l.sinks == nil {
+			l.sinks = newSinkSet(nil)
+		}
+		l.sinks.add(s, SinkOpts{})
+	}
+}
+
+// syslogPaths are the local syslog sockets
+var syslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogDialTimeout limits connecting and writing to the syslog server
+var syslogDialTimeout = time.Second
+
+// syslogRedial is the first and the longest delay between reconnects
+var syslogRedial = [2]time.Duration{time.Second, 30 * time.Second}
+
+// Syslog sends log lines as syslog messages
+// it implements hclog.SinkAdapter
+//
+// If sending fails the connection is redialed in the background,
+// lines are dropped (see Statistics) until it is connected again.
+type Syslog struct {
+	opts SyslogOpts
+	pid  string
+	now  func() time.Time
+
+	mu   sync.Mutex
+	conn net.Conn
+	// network is the network connected to
+	network string
+	// redialing is set while reconnecting in the background
+	redialing bool
+	// done is closed by Close
+	done chan struct{}
+}
+
+// NewSyslog connects to a syslog server
+func NewSyslog(opts SyslogOpts) (*Syslog, error) {
+	if opts.Facility == 0 {
+		opts.Facility = 1
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.SDID == "" {
+		opts.SDID = "hcl@32473"
+	}
+	s := &Syslog{opts: opts, pid: strconv.Itoa(os.Getpid()), now: time.Now, done: make(chan struct{})}
+	conn, network, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	s.conn, s.network = conn, network
+	return s, nil
+}
+
+// dial connects to the syslog server
+func (s *Syslog) dial() (net.Conn, string, error) {
+	if s.opts.Network != "" {
+		c, err := net.DialTimeout(s.opts.Network, s.opts.Addr, syslogDialTimeout)
+		return c, s.opts.Network, err
+	}
+	paths := syslogPaths
+	if s.opts.Addr != "" {
+		paths = []string{s.opts.Addr}
+	}
+	var errs []error
+	for _, path := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			c, err := net.DialTimeout(network, path, syslogDialTimeout)
+			if err == nil {
+				return c, network, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return nil, "", fmt.Errorf("no local syslog: %w", errors.Join(errs...))
+}
+
+// Accept sends a log line
+func (s *Syslog) Accept(name string, level hclog.Level, msg string, args ...interface{}) {
+	var frame string
+	if s.opts.Format == RFC3164 {
+		frame = s.rfc3164(name, level, msg, args)
+	} else {
+		frame = s.rfc5424(name, level, msg, args)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.send(frame); err != nil {
+		droppedLines.Add(1)
+		if s.conn != nil {
+			// the server may have been restarted
+			s.conn.Close()
+			s.conn = nil
+		}
+		if !s.redialing && s.done != nil {
+			s.redialing = true
+			go s.redial()
+		}
+	}
+}
+
+// redial reconnects with a growing delay until it succeeds or s is closed
+func (s *Syslog) redial() {
+	delay := syslogRedial[0]
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+		conn, network, err := s.dial()
+		if err == nil {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.redialing = false
+			select {
+			case <-s.done:
+				conn.Close()
+			default:
+				s.conn, s.network = conn, network
+			}
+			return
+		}
+		if delay *= 2; delay > syslogRedial[1] {
+			delay = syslogRedial[1]
+		}
+	}
+}
+
+// send writes a frame to the connection
+// s.mu must be held
+func (s *Syslog) send(frame string) error {
+	if s.conn == nil {
+		return net.ErrClosed
+	}
+	switch s.network {
+	case "tcp", "tcp4", "tcp6":
+		// octet counting (RFC 6587)
+		frame = strconv.Itoa(len(frame)) + " " + frame
+	case "unix":
+		// frames end at a newline (RFC 6587 non-transparent framing)
+		// newlines of multi-line messages are escaped to keep them in one frame
+		frame = strings.ReplaceAll(frame, "\n", `\n`) + "\n"
+	}
+	// a stalled server must not stall the logging goroutines
+	s.conn.SetWriteDeadline(time.Now().Add(syslogDialTimeout))
+	_, err := s.conn.Write([]byte(frame))
+	return err
+}
+
+// Close closes the connection and stops reconnecting
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		select {
+		case <-s.done:
+		default:
+			close(s.done)
+		}
+	}
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// SyslogSeverity maps hcl levels to syslog severities
+func SyslogSeverity(level hclog.Level) int {
+	switch level {
+	case hclog.Trace, hclog.Debug:
+		return 7 // debug
+	case hclog.Info:
+		return 6 // info
+	case hclog.Warn:
+		return 4 // warning
+	default:
+		return 3 // err
+	}
+}
+
+func (s *Syslog) pri(level hclog.Level) string {
+	return "<" + strconv.Itoa(s.opts.Facility*8+SyslogSeverity(level)) + ">"
+}
+
+// rfc5424 formats a frame: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (s *Syslog) rfc5424(name string, level hclog.Level, msg string, args []interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(s.pri(level))
+	sb.WriteString("1 ")
+	sb.WriteString(s.now().Format("2006-01-02T15:04:05.000000Z07:00"))
+	sb.WriteByte(' ')
+	sb.WriteString(syslogHeader(s.opts.Hostname, 255))
+	sb.WriteByte(' ')
+	app := s.opts.AppName
+	if app == "" {
+		app = name
+	} else if name != "" {
+		args = append([]interface{}{"logger", name}, args...)
+	}
+	sb.WriteString(syslogHeader(app, 48))
+	sb.WriteByte(' ')
+	sb.WriteString(s.pid)
+	sb.WriteString(" - ")
+	pairs := stringPairs(args)
+	if len(pairs) < 1 {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteByte('[')
+		sb.WriteString(s.opts.SDID)
+		for _, p := range pairs {
+			sb.WriteByte(' ')
+			sb.WriteString(sdName(p[0]))
+			sb.WriteString(`="`)
+			sb.WriteString(sdValue.Replace(p[1]))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte(']')
+	}
+	if msg != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(msg)
+	}
+	return sb.String()
+}
+
+// rfc3164 formats a frame: <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG k=v
+func (s *Syslog) rfc3164(name string, level hclog.Level, msg string, args []interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(s.pri(level))
+	sb.WriteString(s.now().Format(time.Stamp))
+	sb.WriteByte(' ')
+	sb.WriteString(syslogHeader(s.opts.Hostname, 255))
+	sb.WriteByte(' ')
+	tag := s.opts.AppName
+	if tag == "" {
+		tag = name
+	}
+	sb.WriteString(syslogHeader(tag, 32))
+	sb.WriteString("[" + s.pid + "]: ")
+	sb.WriteString(msg)
+	for _, p := range stringPairs(args) {
+		sb.WriteByte(' ')
+		sb.WriteString(p[0])
+		sb.WriteByte('=')
+		if strings.ContainsAny(p[1], " \"=") {
+			sb.WriteString(strconv.Quote(p[1]))
+		} else {
+			sb.WriteString(p[1])
+		}
+	}
+	return sb.String()
+}
+
+// syslogHeader returns the printable ascii part of a header field or "-"
+func syslogHeader(s string, max int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		if s[i] > 32 && s[i] < 127 {
+			b = append(b, s[i])
+		}
+	}
+	if len(b) < 1 {
+		return "-"
+	}
+	return string(b)
+}
+
+// sdName returns a valid SD-NAME: printable ascii without = ] " and space
+func sdName(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < 32; i++ {
+		c := s[i]
+		if c <= 32 || c >= 127 || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	if len(b) < 1 {
+		return "_"
+	}
+	return string(b)
+}
+
+// sdValue escapes a PARAM-VALUE
+var sdValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
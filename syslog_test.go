@@ -0,0 +1,224 @@
+package hcl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var syslogTime = time.Date(2022, 2, 25, 9, 40, 12, 123456000, time.UTC)
+
+func TestSyslogSeverity(t *testing.T) {
+	assert.Equal(t, 7, SyslogSeverity(hclog.Trace))
+	assert.Equal(t, 7, SyslogSeverity(hclog.Debug))
+	assert.Equal(t, 6, SyslogSeverity(hclog.Info))
+	assert.Equal(t, 4, SyslogSeverity(hclog.Warn))
+	assert.Equal(t, 3, SyslogSeverity(hclog.Error))
+}
+
+func TestSyslogFrames(t *testing.T) {
+	s := &Syslog{opts: SyslogOpts{Facility: 3, Hostname: "host", SDID: "hcl@32473"}, pid: "42"}
+	s.now = func() time.Time { return syslogTime }
+	args := []interface{}{"user", "joe", "err", errors.New(`bad "quote"]`), "a b=c", 1}
+	assert.Equal(t,
+		`<27>1 2022-02-25T09:40:12.123456Z host app.web 42 - [hcl@32473 user="joe" err="bad \"quote\"\]" a_b_c="1"] failed`,
+		s.rfc5424("app.web", hclog.Error, "failed", args))
+	assert.Equal(t, `<30>1 2022-02-25T09:40:12.123456Z host app 42 - - started`, s.rfc5424("app", hclog.Info, "started", nil))
+	assert.Equal(t,
+		`<31>Feb 25 09:40:12 host app.web[42]: debug user=joe err="bad \"quote\"]" a b=c=1`,
+		s.rfc3164("app.web", hclog.Debug, "debug", args))
+
+	s.opts.AppName = "myapp"
+	assert.Equal(t, `<28>1 2022-02-25T09:40:12.123456Z host myapp 42 - [hcl@32473 logger="app.web"] warn`, s.rfc5424("app.web", hclog.Warn, "warn", nil))
+}
+
+func TestSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	keepDefault(t)
+	l := newLogger(WithName("udp"), WithWriter(&bytes.Buffer{}), WithLevel(hclog.Info), WithStdlib(false),
+		WithSyslog(SyslogOpts{Network: "udp", Addr: pc.LocalAddr().String()}))
+	defer l.Close()
+	l.With("conn", 1).Warn("udp message", "k", "v")
+	l.Debug("not written")
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	assert.NoError(t, err)
+	frame := string(buf[:n])
+	assert.True(t, strings.HasPrefix(frame, "<12>1 "), frame)
+	assert.Contains(t, frame, ` udp `)
+	assert.True(t, strings.HasSuffix(frame, `[hcl@32473 conn="1" k="v"] udp message`), frame)
+}
+
+func TestSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	frames := make(chan string, 2)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		r := bufio.NewReader(c)
+		for {
+			// octet counting: LEN SP FRAME
+			lenStr, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(lenStr))
+			b := make([]byte, n)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return
+			}
+			frames <- string(b)
+		}
+	}()
+	keepDefault(t)
+	l := newLogger(WithName("tcp"), WithWriter(&bytes.Buffer{}), WithLevel(hclog.Info), WithStdlib(false),
+		WithSyslog(SyslogOpts{Network: "tcp", Addr: ln.Addr().String(), Format: RFC3164}))
+	defer l.Close()
+	l.Info("first")
+	l.Error("second line")
+	for _, want := range []string{"<14>", "<11>"} {
+		select {
+		case f := <-frames:
+			assert.True(t, strings.HasPrefix(f, want), f)
+		case <-time.After(time.Second):
+			t.Fatal("no frame received")
+		}
+	}
+}
+
+func TestSyslogRedial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	addr := ln.Addr().String()
+	prevRedial := syslogRedial
+	syslogRedial = [2]time.Duration{10 * time.Millisecond, 10 * time.Millisecond}
+	defer func() { syslogRedial = prevRedial }()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+	s, err := NewSyslog(SyslogOpts{Network: "tcp", Addr: addr})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	// the server goes away: lines are dropped without blocking
+	ln.Close()
+	(<-accepted).Close()
+	dropped := droppedLines.Load()
+	deadline := time.Now().Add(5 * time.Second)
+	for droppedLines.Load() == dropped && time.Now().Before(deadline) {
+		s.Accept("tcp", hclog.Info, "lost")
+		time.Sleep(time.Millisecond)
+	}
+	assert.Greater(t, droppedLines.Load(), dropped)
+
+	// and comes back
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	c, err := ln.Accept()
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.conn != nil
+	}, time.Second, time.Millisecond)
+	s.Accept("tcp", hclog.Info, "reconnected")
+	c.SetReadDeadline(time.Now().Add(time.Second))
+	frame, err := bufio.NewReader(c).ReadString('d')
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(frame, " reconnected"), frame)
+}
+
+func TestSyslogUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	s, err := NewSyslog(SyslogOpts{Addr: path})
+	assert.NoError(t, err)
+	defer s.Close()
+	s.Accept("local", hclog.Info, "local message")
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(buf[:n]), " local "+s.pid+" - - local message"), string(buf[:n]))
+}
+
+func TestSyslogUnixStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	frames := make(chan string, 2)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		r := bufio.NewReader(c)
+		for {
+			frame, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			frames <- strings.TrimSuffix(frame, "\n")
+		}
+	}()
+	s, err := NewSyslog(SyslogOpts{Network: "unix", Addr: path})
+	assert.NoError(t, err)
+	defer s.Close()
+	s.Accept("local", hclog.Error, "first line\nsecond line")
+	s.Accept("local", hclog.Info, "next")
+	for _, want := range []string{" - - first line\\nsecond line", " - - next"} {
+		select {
+		case f := <-frames:
+			assert.True(t, strings.HasSuffix(f, want), f)
+		case <-time.After(time.Second):
+			t.Fatal("no frame received")
+		}
+	}
+}
+
+func TestSyslogConnectError(t *testing.T) {
+	keepDefault(t)
+	var buf bytes.Buffer
+	newLogger(WithWriter(&buf), WithStdlib(false), WithSyslog(SyslogOpts{Addr: filepath.Join(t.TempDir(), "missing")}))
+	assert.Contains(t, buf.String(), "[WARN]  go-hcl: cannot connect syslog: no local syslog")
+}
@@ -0,0 +1,379 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// WithLineTemplate sets the layout of text lines
+//
+// The placeholders are:
+//
+//	{time}   the time formatted by the TimeFormat of the logger
+//	{level}  the level in upper case: TRACE, DEBUG, INFO, WARN, ERROR
+//	{name}   the name of the logger
+//	{caller} the file and line of the log call: main.go:42
+//	{msg}    the message
+//	{fields} the key/value pairs: k=v k2="v 2"
+//
+// A placeholder takes an optional width and color: {name:>10.10|cyan}
+// :10 pads to 10 characters, :>10 pads on the left, .10 truncates to 10 characters.
+// The colors are black, red, green, yellow, blue, magenta, cyan, white, gray, bold, faint
+// and level (the hclog color of the level), combined by +: {msg|bold+red}.
+// They are written if the Color of the logger options is set (see HCL_COLOR).
+// {{ and }} are written as { and }.
+//
+// Placeholders not in the template are omitted: "{level}: {msg}" writes neither time nor name.
+// An empty {time} (see HCL_TIME_FORMAT), {name}, {caller} or {fields} is omitted
+// with the text between it and the message.
+// An invalid template is logged at creation and the default layout is used.
+func WithLineTemplate(tmpl string) LoggerOpt {
+	return func(l *Logger) {
+		t, err := parseLineTemplate(tmpl)
+		if err != nil {
+			l.initErrs = append(l.initErrs, fmt.Errorf("invalid line template: %w", err))
+			return
+		}
+		l.template = t
+	}
+}
+
+// templateField is the value of a template segment
+type templateField int
+
+const (
+	fieldLiteral templateField = iota
+	fieldTime
+	fieldLevel
+	fieldName
+	fieldCaller
+	fieldMsg
+	fieldFields
+)
+
+var templateFields = map[string]templateField{
+	"time":   fieldTime,
+	"level":  fieldLevel,
+	"name":   fieldName,
+	"caller": fieldCaller,
+	"msg":    fieldMsg,
+	"fields": fieldFields,
+}
+
+// optional reports if the separators of an empty field are omitted
+func (f templateField) optional() bool {
+	return f == fieldTime || f == fieldName || f == fieldCaller || f == fieldFields
+}
+
+// templateSegment is a literal or a placeholder of a line template
+type templateSegment struct {
+	field   templateField
+	literal string
+	// width pads the value, right aligns it
+	width int
+	right bool
+	// max truncates the value if > 0
+	max int
+	// colors are the escape sequences of the value, byLevel colors by level
+	colors  string
+	byLevel bool
+}
+
+// lineTemplate formats text lines
+type lineTemplate struct {
+	segments []templateSegment
+	// msg is the index of {msg}, len(segments) if it is missing
+	msg int
+	// caller is set if the template uses {caller}
+	caller     bool
+	timeFormat string
+	color      bool
+}
+
+var templateColors = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+	"bold":    "1",
+	"faint":   "2",
+}
+
+// levelColors are the colors hclog uses for the levels
+var levelColors = map[hclog.Level]string{
+	hclog.Trace: "32",
+	hclog.Debug: "97",
+	hclog.Info:  "94",
+	hclog.Warn:  "93",
+	hclog.Error: "91",
+}
+
+// parseLineTemplate parses a template (see WithLineTemplate)
+func parseLineTemplate(tmpl string) (*lineTemplate, error) {
+	t := &lineTemplate{msg: -1, timeFormat: TimeFormat}
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			t.segments = append(t.segments, templateSegment{literal: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		switch {
+		case c == '{' && strings.HasPrefix(tmpl[i:], "{{"), c == '}' && strings.HasPrefix(tmpl[i:], "}}"):
+			lit.WriteByte(c)
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("unexpected } at %d", i)
+		case c == '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed { at %d", i)
+			}
+			seg, err := parsePlaceholder(tmpl[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			switch seg.field {
+			case fieldMsg:
+				t.msg = len(t.segments)
+			case fieldCaller:
+				t.caller = true
+			}
+			t.segments = append(t.segments, seg)
+			i += end
+		default:
+			lit.WriteByte(c)
+		}
+	}
+	flush()
+	if t.msg < 0 {
+		t.msg = len(t.segments)
+	}
+	return t, nil
+}
+
+// parsePlaceholder parses name[:[>]width][.max][|color[+color]]
+func parsePlaceholder(p string) (templateSegment, error) {
+	var seg templateSegment
+	spec, colors, hasColor := strings.Cut(p, "|")
+	name, format, hasFormat := strings.Cut(spec, ":")
+	field, ok := templateFields[name]
+	if !ok {
+		return seg, fmt.Errorf("unknown placeholder {%s}", name)
+	}
+	seg.field = field
+	if hasFormat {
+		if strings.HasPrefix(format, ">") {
+			seg.right = true
+			format = format[1:]
+		}
+		width, max, hasMax := strings.Cut(format, ".")
+		var err error
+		if width != "" {
+			if seg.width, err = strconv.Atoi(width); err != nil || seg.width < 0 {
+				return seg, fmt.Errorf("invalid width of {%s}: %q", name, width)
+			}
+		}
+		if hasMax {
+			if seg.max, err = strconv.Atoi(max); err != nil || seg.max < 1 {
+				return seg, fmt.Errorf("invalid truncation of {%s}: %q", name, max)
+			}
+		}
+	}
+	if hasColor {
+		var codes []string
+		for _, c := range strings.Split(colors, "+") {
+			if c == "level" {
+				seg.byLevel = true
+				continue
+			}
+			code, ok := templateColors[c]
+			if !ok {
+				return seg, fmt.Errorf("unknown color %q of {%s}", c, name)
+			}
+			codes = append(codes, code)
+		}
+		if len(codes) > 0 {
+			seg.colors = strings.Join(codes, ";")
+		}
+	}
+	return seg, nil
+}
+
+// forOutput returns a copy of t using the time format and color of opts
+func (t *lineTemplate) forOutput(w io.Writer, opts *hclog.LoggerOptions) *lineTemplate {
+	c := *t
+	c.timeFormat = opts.TimeFormat
+	if c.timeFormat == "" {
+		c.timeFormat = TimeFormat
+	}
+	switch opts.Color {
+	case hclog.ForceColor:
+		c.color = true
+	case hclog.AutoColor:
+		c.color = isTerminal(w)
+	default:
+		c.color = false
+	}
+	return &c
+}
+
+// isTerminal reports if w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// appendLine appends a line formatted by the template
+// it is a lineEncoder, the time is omitted if now is zero
+func (t *lineTemplate) appendLine(b []byte, now time.Time, name string, level hclog.Level, msg string, args []interface{}) []byte {
+	var caller string
+	if t.caller {
+		caller = callerLocation()
+	}
+	value := func(f templateField) string {
+		switch f {
+		case fieldTime:
+			if now.IsZero() {
+				return ""
+			}
+			return now.Format(t.timeFormat)
+		case fieldLevel:
+			return strings.ToUpper(level.String())
+		case fieldName:
+			return name
+		case fieldCaller:
+			return caller
+		case fieldMsg:
+			return msg
+		case fieldFields:
+			var fb []byte
+			for i, p := range stringPairs(args) {
+				if i > 0 {
+					fb = append(fb, ' ')
+				}
+				fb = append(fb, p[0]...)
+				fb = append(fb, '=')
+				fb = appendLogfmtValue(fb, p[1])
+			}
+			return string(fb)
+		}
+		return ""
+	}
+	values := make([]string, len(t.segments))
+	for i, seg := range t.segments {
+		values[i] = value(seg.field)
+	}
+	empty := func(i int) bool {
+		return i >= 0 && i < len(t.segments) && t.segments[i].field.optional() && values[i] == ""
+	}
+	for i, seg := range t.segments {
+		if seg.field == fieldLiteral {
+			if i < t.msg && empty(i-1) || i > t.msg && empty(i+1) {
+				continue
+			}
+			b = append(b, seg.literal...)
+			continue
+		}
+		if seg.field.optional() && values[i] == "" {
+			continue
+		}
+		b = seg.append(b, values[i], level, t.color)
+	}
+	return append(b, '\n')
+}
+
+// append appends v truncated, padded and colored
+func (seg templateSegment) append(b []byte, v string, level hclog.Level, color bool) []byte {
+	n := utf8.RuneCountInString(v)
+	if seg.max > 0 && n > seg.max {
+		i := 0
+		for j := range v {
+			if i == seg.max {
+				v = v[:j]
+				break
+			}
+			i++
+		}
+		n = seg.max
+	}
+	colors := seg.colors
+	if seg.byLevel {
+		if colors != "" {
+			colors += ";"
+		}
+		colors += levelColors[level]
+	}
+	color = color && colors != ""
+	pad := seg.width - n
+	if seg.right {
+		b = appendSpaces(b, pad)
+	}
+	if color {
+		b = append(b, "\x1b["+colors+"m"...)
+	}
+	b = append(b, v...)
+	if color {
+		b = append(b, "\x1b[0m"...)
+	}
+	if !seg.right {
+		b = appendSpaces(b, pad)
+	}
+	return b
+}
+
+func appendSpaces(b []byte, n int) []byte {
+	for ; n > 0; n-- {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// callerLocation returns file.go:line of the first frame outside of hcl and the log packages
+func callerLocation() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		f, more := frames.Next()
+		if !isLogFrame(f) {
+			return filepath.Base(f.File) + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isLogFrame reports if f is in hcl (not its tests), hclog or the stdlib log packages
+func isLogFrame(f runtime.Frame) bool {
+	if filepath.Dir(f.File) == hclDir && !strings.HasSuffix(f.File, "_test.go") {
+		return true
+	}
+	for _, p := range []string{"log.", "log/slog.", "github.com/hashicorp/go-hclog."} {
+		if strings.HasPrefix(f.Function, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package hcl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineTemplateGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		tmpl  string
+		color hclog.ColorOption
+	}{
+		{name: "time", tmpl: "{time} {msg}"},
+		{name: "level", tmpl: "{level:5}|{level:>5}|{level:.1}: {msg}"},
+		{name: "name", tmpl: "{name:6.6}: {msg}"},
+		{name: "caller", tmpl: "{caller} {msg}"},
+		{name: "msg", tmpl: "{level}: {msg:.8}"},
+		{name: "fields", tmpl: "{{{level}}} {msg} {fields}"},
+		{name: "fields_sep", tmpl: "{msg}: {fields}"},
+		{name: "color", tmpl: "{time|gray} {level:5|level+bold} {name|cyan}: {msg}: {fields|faint}", color: hclog.ForceColor},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Trace), WithStdlib(false),
+				WithLineTemplate(tc.tmpl), WithLoggerOptions(&hclog.LoggerOptions{TimeFormat: TimeFormat, Color: tc.color}), withFixedTime())
+			l.Info("started", "port", 8080)
+			l.Named("web").With("req", "r-1").Warn("slow request", "path", "/a b")
+			l.Error("failed", "err", errors.New("connection refused"))
+			l.ResetNamed("").Debugf("no name %d", 1)
+			l.Trace("no fields")
+			assertGolden(t, "template_"+tc.name, out.Bytes())
+		})
+	}
+}
+
+func TestLineTemplateInvalid(t *testing.T) {
+	for _, tmpl := range []string{"{nope}", "{msg", "msg}", "{level:x}", "{name:.0}", "{msg|pink}"} {
+		_, err := parseLineTemplate(tmpl)
+		assert.Error(t, err, tmpl)
+	}
+
+	var out bytes.Buffer
+	l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Info), WithStdlib(false),
+		WithLoggerOptions(&hclog.LoggerOptions{DisableTime: true}), WithLineTemplate("{nope}"))
+	assert.Equal(t, "[WARN]  app: invalid line template: unknown placeholder {nope}\n", out.String())
+	assert.Nil(t, l.template)
+}
+
+func TestLineTemplateCallerSlog(t *testing.T) {
+	var out bytes.Buffer
+	l := newLogger(WithName("app"), WithWriter(&out), WithLevel(hclog.Info), WithStdlib(false),
+		WithLineTemplate("{caller}: {msg}"))
+	l.Slog().Info("via slog")
+	l.StandardLogger(nil).Print("via stdlib")
+	assert.Regexp(t, `^template_test.go:\d+: via slog\ntemplate_test.go:\d+: via stdlib\n$`, out.String())
+}
@@ -0,0 +1,95 @@
+package hcl
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const (
+	// TraceIDKey is the key of the trace id added to log lines
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the key of the span id added to log lines
+	SpanIDKey = "span_id"
+)
+
+// TraceExtractor returns the trace and span id of the span carried by ctx
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var (
+	traceMu         sync.RWMutex
+	traceExtractors []TraceExtractor
+)
+
+// RegisterTraceExtractor adds an extractor of trace and span ids
+// extractors are asked in the order of registration, the W3C traceparent
+// of NewTraceparentContext is used if no extractor finds a span
+// importing github.com/vogtp/go-hcl/hclotel registers OpenTelemetry
+func RegisterTraceExtractor(e TraceExtractor) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	traceExtractors = append(traceExtractors, e)
+}
+
+type traceparentCtxKey struct{}
+
+// NewTraceparentContext returns a context carrying a W3C traceparent header
+// like "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func NewTraceparentContext(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, traceparentCtxKey{}, traceparent)
+}
+
+// ParseTraceparent returns the trace and span id of a W3C traceparent header
+func ParseTraceparent(traceparent string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	// version 00 has exactly 4 parts, later versions may add more
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	traceID, spanID = parts[1], parts[2]
+	if !isTraceHex(parts[0], 2) || !isTraceHex(traceID, 32) || !isTraceHex(spanID, 16) || !isTraceHex(parts[3], 2) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// isTraceHex checks if s is a lower case hex string of length n which is not all zero
+func isTraceHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	zero := true
+	for _, c := range s {
+		switch {
+		case c == '0':
+		case '1' <= c && c <= '9', 'a' <= c && c <= 'f':
+			zero = false
+		default:
+			return false
+		}
+	}
+	// the version and flags may be 00
+	return !zero || n == 2
+}
+
+// TraceFields returns the trace_id and span_id key/value pairs of the span carried by ctx
+// nil is returned if ctx carries no span
+func TraceFields(ctx context.Context) []interface{} {
+	traceMu.RLock()
+	extractors := traceExtractors
+	traceMu.RUnlock()
+	for _, e := range extractors {
+		if traceID, spanID, ok := e(ctx); ok {
+			return []interface{}{TraceIDKey, traceID, SpanIDKey, spanID}
+		}
+	}
+	if tp, ok := ctx.Value(traceparentCtxKey{}).(string); ok {
+		if traceID, spanID, ok := ParseTraceparent(tp); ok {
+			return []interface{}{TraceIDKey, traceID, SpanIDKey, spanID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package hcl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/vogtp/go-hcl"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		tp      string
+		traceID string
+		spanID  string
+		ok      bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "", "", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", "", "", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-4bf92f3577b34da6-00f067aa0ba902b7-01", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tc := range tests {
+		traceID, spanID, ok := hcl.ParseTraceparent(tc.tp)
+		assert.Equal(t, tc.ok, ok, tc.tp)
+		assert.Equal(t, tc.traceID, traceID, tc.tp)
+		assert.Equal(t, tc.spanID, spanID, tc.tp)
+	}
+}
+
+func TestTraceFields(t *testing.T) {
+	buf := &syncBuffer{}
+	l := hcl.New(hcl.WithName("trace"), hcl.WithWriter(buf), hcl.WithLevel(hclog.Info), hcl.WithStdlib(false))
+
+	ctx := hcl.NewTraceparentContext(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx = hcl.WithContextFields(ctx, "request", 1)
+	l.WithContext(ctx).Info("with")
+	// hclog sorts the args of With
+	assert.Contains(t, buf.String(), "trace: with: request=1 span_id=00f067aa0ba902b7 trace_id=4bf92f3577b34da6a3ce929d0e0e4736\n")
+	hcl.InfoCtx(ctx, "ctx")
+	assert.Contains(t, buf.String(), "trace: ctx: trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 request=1\n")
+
+	assert.Nil(t, hcl.TraceFields(hcl.NewTraceparentContext(context.Background(), "invalid")))
+}